@@ -13,7 +13,10 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/mfahlandt/lwcn/internal/ai"
+	"github.com/mfahlandt/lwcn/internal/config"
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/news"
+	"github.com/mfahlandt/lwcn/internal/window"
 )
 
 func main() {
@@ -24,19 +27,29 @@ func main() {
 
 	releasesFile := flag.String("releases", "", "Path to releases JSON file")
 	newsFile := flag.String("news", "", "Path to news JSON file")
+	newsConfig := flag.String("news-config", "config/news-sources.yaml", "News sources config with the relevance cutoff (scoring.min_score, scoring.max_items)")
+	minScore := flag.Float64("min-news-score", -1, "Only send news scoring at least this to the model (default: scoring.min_score from -news-config)")
+	maxNews := flag.Int("max-news", -1, "Send at most this many news items, highest score first (default: scoring.max_items from -news-config)")
+	advisoriesFile := flag.String("advisories", "", "Path to security advisories JSON file (default: data/advisories-*.json of the releases window)")
+	changesFile := flag.String("landscape-changes", "", "Path to CNCF project changes JSON file (default: data/landscape-changes-*.json of the releases window)")
 	outputDir := flag.String("output", "website/content/newsletter", "Output directory for drafts")
 	linkedinOnly := flag.Bool("linkedin", false, "Generate only LinkedIn post")
+	provider := flag.String("provider", "", "LLM provider: gemini or openai (default: $LLM_PROVIDER or gemini)")
+	modelName := flag.String("model", "", "LLM model name (default: $LLM_MODEL or the provider default)")
 	flag.Parse()
 
 	log.Println("Starting AI Newsletter Generator...")
 
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		log.Fatal("GEMINI_API_KEY environment variable required. Set it in .env file or environment.")
+	providerCfg := ai.ProviderConfigFromEnv(*provider)
+	if *modelName != "" {
+		providerCfg.Model = *modelName
 	}
-	log.Println("GEMINI_API_KEY found")
 
-	releases, err := loadReleases(*releasesFile)
+	releasesPath := *releasesFile
+	if releasesPath == "" {
+		releasesPath = window.Latest("data", "releases-")
+	}
+	releases, err := loadReleases(releasesPath)
 	if err != nil {
 		log.Fatalf("Failed to load releases: %v", err)
 	}
@@ -48,6 +61,8 @@ func main() {
 	}
 	log.Printf("Loaded %d news items", len(news))
 
+	news = selectRelevantNews(news, *newsConfig, *minScore, *maxNews)
+
 	// Load neutral activity stats (optional — missing file is OK)
 	stats, err := loadStats("")
 	if err != nil {
@@ -56,29 +71,60 @@ func main() {
 		log.Printf("Loaded %d repo stats entries", len(stats))
 	}
 
+	// Security advisories are optional as well; they feed the security
+	// section. Only those of the releases' window are used, so a missing file
+	// leaves the section out instead of repeating an earlier week.
+	advisoriesPath := *advisoriesFile
+	if advisoriesPath == "" {
+		advisoriesPath = window.Sibling(releasesPath, "releases-", "advisories-")
+	}
+	advisories, err := loadAdvisories(advisoriesPath)
+	if err != nil {
+		log.Printf("No advisories file loaded: %v", err)
+	} else {
+		log.Printf("Loaded %d security advisories", len(advisories))
+	}
+
+	// CNCF project changes from the landscape sync are optional too
+	changesPath := *changesFile
+	if changesPath == "" {
+		changesPath = window.Sibling(releasesPath, "releases-", "landscape-changes-")
+	}
+	landscapeChanges, err := loadLandscapeChanges(changesPath)
+	if err != nil {
+		log.Printf("No landscape changes file loaded: %v", err)
+	} else {
+		log.Printf("Loaded CNCF project changes: %d joined, %d promoted, %d archived, %d removed",
+			len(landscapeChanges.Joined), len(landscapeChanges.Promoted), len(landscapeChanges.Archived), len(landscapeChanges.Removed))
+	}
+
 	ctx := context.Background()
 
-	gemini, err := ai.NewGeminiClient(ctx, apiKey)
+	model, err := ai.NewTextModel(ctx, providerCfg)
 	if err != nil {
-		log.Fatalf("Failed to create Gemini client: %v", err)
+		log.Fatalf("Failed to create LLM client: %v", err)
 	}
-	defer gemini.Close()
+	generator := ai.NewGenerator(model)
+	defer generator.Close()
+	log.Printf("Using LLM provider: %s", generator.Name())
 
 	// Generate LinkedIn post
 	if *linkedinOnly {
-		generateLinkedInPosts(ctx, gemini, releases, news, *outputDir)
+		generateLinkedInPosts(ctx, generator, releases, news, *outputDir)
 		return
 	}
 
 	// Generate full newsletter
-	log.Println("Generating newsletter with Gemini...")
-	newsletter, err := gemini.GenerateNewsletter(ctx, releases, news, stats)
+	log.Printf("Generating newsletter with %s...", generator.Name())
+	newsletter, err := generator.GenerateNewsletter(ctx, releases, news, stats)
 	if err != nil {
 		log.Fatalf("Failed to generate newsletter: %v", err)
 	}
+	newsletter.Advisories = advisories
+	newsletter.LandscapeChanges = landscapeChanges
 
-	generator := ai.NewDraftGenerator(*outputDir)
-	draftPath, err := generator.GenerateDraft(newsletter)
+	draftGenerator := ai.NewDraftGenerator(*outputDir)
+	draftPath, err := draftGenerator.GenerateDraft(newsletter)
 	if err != nil {
 		log.Fatalf("Failed to generate draft: %v", err)
 	}
@@ -86,13 +132,13 @@ func main() {
 	log.Printf("Newsletter draft created: %s", draftPath)
 
 	// Also generate LinkedIn posts (newsletter + short teaser)
-	generateLinkedInPosts(ctx, gemini, releases, news, *outputDir)
+	generateLinkedInPosts(ctx, generator, releases, news, *outputDir)
 }
 
-func generateLinkedInPosts(ctx context.Context, gemini *ai.GeminiClient, releases []models.Release, news []models.NewsItem, outputDir string) {
+func generateLinkedInPosts(ctx context.Context, generator *ai.Generator, releases []models.Release, news []models.NewsItem, outputDir string) {
 	// Generate LinkedIn Newsletter post (longer version with website link)
-	log.Println("Generating LinkedIn newsletter post with Gemini...")
-	linkedinPost, err := gemini.GenerateLinkedInPost(ctx, releases, news)
+	log.Printf("Generating LinkedIn newsletter post with %s...", generator.Name())
+	linkedinPost, err := generator.GenerateLinkedInPost(ctx, releases, news)
 	if err != nil {
 		log.Printf("Warning: Failed to generate LinkedIn newsletter post: %v", err)
 		return
@@ -105,13 +151,13 @@ func generateLinkedInPosts(ctx context.Context, gemini *ai.GeminiClient, release
 	fmt.Println("--- End ---")
 
 	// Generate all three short-format posts (LinkedIn teaser, tweet, bluesky)
-	// in a SINGLE combined Gemini call to save free-tier quota.
+	// in a SINGLE combined model call to save free-tier quota.
 	now := time.Now()
 	year, week := now.ISOWeek()
 	newsletterURL := fmt.Sprintf("https://lwcn.dev/newsletter/%d-week-%02d/", year, week)
 
-	log.Println("Generating short-format posts (LinkedIn teaser + tweet + bluesky) in ONE Gemini call...")
-	shorts, err := gemini.GenerateSocialShorts(ctx, linkedinPost, newsletterURL)
+	log.Println("Generating short-format posts (LinkedIn teaser + tweet + bluesky) in ONE model call...")
+	shorts, err := generator.GenerateSocialShorts(ctx, linkedinPost, newsletterURL)
 	if err != nil {
 		log.Printf("Warning: Failed to generate social shorts: %v", err)
 		return
@@ -147,9 +193,36 @@ func saveLinkedInFile(outputDir, content, suffix string) string {
 	return outputPath
 }
 
+// selectRelevantNews applies the relevance cutoff to scored news. Flags
+// override the scoring section of the news config; files crawled without
+// scoring are used as they are.
+func selectRelevantNews(items []models.NewsItem, configPath string, minScore float64, maxItems int) []models.NewsItem {
+	if !news.Scored(items) {
+		log.Println("News items carry no relevance score, using all of them")
+		return items
+	}
+
+	var scoring models.ScoringConfig
+	if cfg, err := config.LoadNewsSources(configPath); err == nil {
+		scoring = cfg.Scoring
+	} else {
+		log.Printf("No news config loaded (%v), using the relevance flags only", err)
+	}
+	if minScore >= 0 {
+		scoring.MinScore = minScore
+	}
+	if maxItems >= 0 {
+		scoring.MaxItems = maxItems
+	}
+
+	selected := news.SelectRelevant(items, scoring.MinScore, scoring.MaxItems)
+	log.Printf("Selected %d of %d news items (score >= %.1f, max %d)", len(selected), len(items), scoring.MinScore, scoring.MaxItems)
+	return selected
+}
+
 func loadReleases(path string) ([]models.Release, error) {
 	if path == "" {
-		path = findLatestFile("data", "releases-")
+		return nil, fmt.Errorf("no releases file found")
 	}
 
 	data, err := os.ReadFile(path)
@@ -167,7 +240,7 @@ func loadReleases(path string) ([]models.Release, error) {
 
 func loadNews(path string) ([]models.NewsItem, error) {
 	if path == "" {
-		path = findLatestFile("data", "news-")
+		path = window.Latest("data", "news-")
 	}
 
 	data, err := os.ReadFile(path)
@@ -188,7 +261,7 @@ func loadNews(path string) ([]models.NewsItem, error) {
 // "Numbers of the Week" section.
 func loadStats(path string) ([]models.RepoStats, error) {
 	if path == "" {
-		path = findLatestFile("data", "stats-")
+		path = window.Latest("data", "stats-")
 	}
 	if path == "" {
 		return nil, fmt.Errorf("no stats file found")
@@ -204,18 +277,37 @@ func loadStats(path string) ([]models.RepoStats, error) {
 	return stats, nil
 }
 
-func findLatestFile(dir, prefix string) string {
-	entries, err := os.ReadDir(dir)
+// loadAdvisories loads a security advisories file (advisories-*.json). A
+// missing file is not fatal.
+func loadAdvisories(path string) ([]models.Advisory, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no advisories file for the releases window")
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return ""
+		return nil, err
 	}
-
-	var latest string
-	for _, entry := range entries {
-		if !entry.IsDir() && len(entry.Name()) > len(prefix) && entry.Name()[:len(prefix)] == prefix {
-			latest = filepath.Join(dir, entry.Name())
-		}
+	var advisories []models.Advisory
+	if err := json.Unmarshal(data, &advisories); err != nil {
+		return nil, err
 	}
+	return advisories, nil
+}
 
-	return latest
+// loadLandscapeChanges loads a CNCF project changes file
+// (landscape-changes-*.json) written by sync-cncf-projects. A missing file is
+// not fatal.
+func loadLandscapeChanges(path string) (*models.LandscapeChanges, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no landscape changes file for the releases window")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var changes models.LandscapeChanges
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, err
+	}
+	return &changes, nil
 }
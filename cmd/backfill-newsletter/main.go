@@ -3,6 +3,8 @@ package main
 // Backfill tool for generating newsletters for past weeks.
 // Use this to regenerate historical newsletters.
 // Run: go run ./cmd/backfill-newsletter -weeks 3
+//  or: go run ./cmd/backfill-newsletter -year 2026 -week 17
+//  or: go run ./cmd/backfill-newsletter -from 2026-04-01 -to 2026-04-07
 
 import (
 	"context"
@@ -21,18 +23,21 @@ import (
 	"github.com/mfahlandt/lwcn/internal/config"
 	"github.com/mfahlandt/lwcn/internal/github"
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/window"
 	"gopkg.in/yaml.v3"
 )
 
 func main() {
 	godotenv.Load()
 
-	weeks := flag.Int("weeks", 3, "Number of past weeks to generate (1-10)")
-	specificWeek := flag.Int("week", 0, "Generate a specific week number (overrides -weeks)")
+	weeks := flag.Int("weeks", 3, "Number of past weeks to generate (1-10); ignored with -week or -from/-to")
+	windowFlags := window.RegisterFlags()
 	configPath := flag.String("config", "config/repositories.yaml", "Path to repositories config")
 	outputDir := flag.String("output", "website/content/newsletter", "Output directory for newsletters")
 	dataDir := flag.String("data", "data", "Output directory for data files")
 	skipCrawl := flag.Bool("skip-crawl", false, "Skip crawling, use existing data files")
+	provider := flag.String("provider", "", "LLM provider: gemini or openai (default: $LLM_PROVIDER or gemini)")
+	modelName := flag.String("model", "", "LLM model name (default: $LLM_MODEL or the provider default)")
 	flag.Parse()
 
 	if *weeks < 1 || *weeks > 10 {
@@ -40,13 +45,13 @@ func main() {
 	}
 
 	githubToken := os.Getenv("GITHUB_TOKEN")
-	geminiKey := os.Getenv("GEMINI_API_KEY")
-
 	if githubToken == "" && !*skipCrawl {
 		log.Fatal("GITHUB_TOKEN environment variable required for crawling")
 	}
-	if geminiKey == "" {
-		log.Fatal("GEMINI_API_KEY environment variable required")
+
+	providerCfg := ai.ProviderConfigFromEnv(*provider)
+	if *modelName != "" {
+		providerCfg.Model = *modelName
 	}
 
 	ctx := context.Background()
@@ -57,57 +62,51 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Create Gemini client
-	gemini, err := ai.NewGeminiClient(ctx, geminiKey)
+	// Create LLM client
+	model, err := ai.NewTextModel(ctx, providerCfg)
 	if err != nil {
-		log.Fatalf("Failed to create Gemini client: %v", err)
+		log.Fatalf("Failed to create LLM client: %v", err)
 	}
-	defer gemini.Close()
+	llm := ai.NewGenerator(model)
+	defer llm.Close()
 
 	// Create GitHub client
 	var ghClient *github.Client
 	if !*skipCrawl {
 		ghClient = github.NewClient(githubToken)
+		ghClient.UseGraphQL(true)
 	}
 
-	// Calculate week ranges
-	// We generate for past completed weeks
+	// Determine which windows to generate. These are the same windows as
+	// the live crawlers use (Monday 00:00 UTC, end exclusive) so a
+	// regenerated edition matches the original
 	now := time.Now()
-	currentYear, currentWeek := now.ISOWeek()
-
-	// Determine which weeks to generate
-	var weeksToGenerate []int
-	if *specificWeek > 0 {
-		// Generate a specific week
-		weeksToGenerate = append(weeksToGenerate, *specificWeek)
+	var windows []window.Window
+	if windowFlags.Explicit() {
+		win, err := windowFlags.Resolve(now)
+		if err != nil {
+			log.Fatalf("Invalid window: %v", err)
+		}
+		windows = append(windows, win)
 	} else {
-		// Generate past N weeks
+		// Generate past N completed weeks, oldest first
 		for i := *weeks; i >= 1; i-- {
-			weekNum := currentWeek - i
-			if weekNum > 0 {
-				weeksToGenerate = append(weeksToGenerate, weekNum)
-			}
+			windows = append(windows, window.LastCompletedWeek(now.AddDate(0, 0, -7*(i-1))))
 		}
 	}
 
-	for _, targetWeek := range weeksToGenerate {
-		// Calculate the Monday of the target week
-		weekStart := getWeekStartForWeek(currentYear, targetWeek)
-		weekEnd := weekStart.AddDate(0, 0, 7).Add(-time.Second) // Sunday 23:59:59
-
-		year, week := weekStart.ISOWeek()
-
+	for _, win := range windows {
 		log.Printf("\n" + strings.Repeat("=", 60))
-		log.Printf("Generating Week %d (%s to %s)", week,
-			weekStart.Format("2006-01-02"),
-			weekEnd.Format("2006-01-02"))
+		log.Printf("Generating %s (%s to %s)", win.Label(),
+			win.Start.Format("2006-01-02"),
+			win.End.Add(-time.Second).Format("2006-01-02"))
 		log.Printf(strings.Repeat("=", 60))
 
 		var releases []models.Release
 
 		if *skipCrawl {
 			// Try to load existing data
-			releasesFile := filepath.Join(*dataDir, fmt.Sprintf("releases-%d-week-%02d.json", year, week))
+			releasesFile := filepath.Join(*dataDir, fmt.Sprintf("releases-%s.json", win.Label()))
 			if data, err := os.ReadFile(releasesFile); err == nil {
 				json.Unmarshal(data, &releases)
 				// Sanitize UTF-8 in release bodies
@@ -117,42 +116,53 @@ func main() {
 				}
 				log.Printf("Loaded %d releases from %s", len(releases), releasesFile)
 			} else {
-				log.Printf("No existing data for week %d, skipping", week)
+				log.Printf("No existing data for %s, skipping", win.Label())
 				continue
 			}
 		} else {
 			// Crawl releases for this week
-			log.Printf("Crawling releases for week %d...", week)
-			releases, err = ghClient.FetchReleasesInRange(ctx, cfg.Repositories, weekStart, weekEnd)
+			log.Printf("Crawling releases for %s...", win.Label())
+			var skipped []github.SkippedRepo
+			releases, skipped, err = ghClient.FetchReleasesInRange(ctx, cfg.Repositories, win.Start, win.End)
 			if err != nil {
 				log.Printf("Error crawling releases: %v", err)
 				continue
 			}
-			log.Printf("Found %d releases", len(releases))
+			log.Printf("Found %d releases (%d repositories skipped)", len(releases), len(skipped))
 
 			// Save releases data
 			os.MkdirAll(*dataDir, 0755)
-			releasesFile := filepath.Join(*dataDir, fmt.Sprintf("releases-%d-week-%02d.json", year, week))
+			releasesFile := filepath.Join(*dataDir, fmt.Sprintf("releases-%s.json", win.Label()))
 			data, _ := json.MarshalIndent(releases, "", "  ")
 			os.WriteFile(releasesFile, data, 0644)
 			log.Printf("Saved releases to %s", releasesFile)
 		}
 
 		if len(releases) == 0 {
-			log.Printf("No releases for week %d, skipping newsletter generation", week)
+			log.Printf("No releases for %s, skipping newsletter generation", win.Label())
 			continue
 		}
 
 		// Generate newsletter
-		log.Printf("Generating newsletter with Gemini AI...")
-		newsletter, err := gemini.GenerateNewsletter(ctx, releases, nil, nil)
+		log.Printf("Generating newsletter with %s...", llm.Name())
+		newsletter, err := llm.GenerateNewsletter(ctx, releases, nil, nil)
 		if err != nil {
 			log.Printf("Error generating newsletter: %v", err)
 			continue
 		}
 
+		// Project changes cannot be recomputed for a past week, so only the
+		// ones the sync saved for it are used
+		changesFile := filepath.Join(*dataDir, fmt.Sprintf("landscape-changes-%s.json", win.Label()))
+		if data, err := os.ReadFile(changesFile); err == nil {
+			var changes models.LandscapeChanges
+			if err := json.Unmarshal(data, &changes); err == nil {
+				newsletter.LandscapeChanges = &changes
+			}
+		}
+
 		// Save newsletter with correct week
-		generator := NewBackfillDraftGenerator(*outputDir, year, week, weekStart)
+		generator := NewBackfillDraftGenerator(*outputDir, win)
 		draftPath, err := generator.GenerateDraft(newsletter)
 		if err != nil {
 			log.Printf("Error saving newsletter: %v", err)
@@ -183,49 +193,39 @@ func getWeekStart(now time.Time, weeksAgo int) time.Time {
 	return monday.AddDate(0, 0, -7*weeksAgo)
 }
 
-// getWeekStartForWeek returns the Monday of a specific ISO week number
-func getWeekStartForWeek(year, week int) time.Time {
-	// Find January 4th of the year (always in week 1 per ISO 8601)
-	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
-
-	// Find the Monday of week 1
-	weekday := int(jan4.Weekday())
-	if weekday == 0 {
-		weekday = 7
-	}
-	week1Monday := jan4.AddDate(0, 0, -(weekday - 1))
-
-	// Add (week - 1) weeks to get to the target week
-	return week1Monday.AddDate(0, 0, (week-1)*7)
-}
-
-// BackfillDraftGenerator generates drafts for specific weeks
+// BackfillDraftGenerator generates drafts for specific weeks or date ranges
 type BackfillDraftGenerator struct {
 	outputDir string
-	year      int
-	week      int
-	weekStart time.Time
+	win       window.Window
 }
 
-func NewBackfillDraftGenerator(outputDir string, year, week int, weekStart time.Time) *BackfillDraftGenerator {
+func NewBackfillDraftGenerator(outputDir string, win window.Window) *BackfillDraftGenerator {
 	return &BackfillDraftGenerator{
 		outputDir: outputDir,
-		year:      year,
-		week:      week,
-		weekStart: weekStart,
+		win:       win,
 	}
 }
 
 func (g *BackfillDraftGenerator) GenerateDraft(newsletter *models.Newsletter) (string, error) {
-	title := fmt.Sprintf("Week %d - %s", g.week, g.weekStart.Format("January 2006"))
-	filename := fmt.Sprintf("%d-week-%02d.md", g.year, g.week)
+	// Editions are named after their window label, like the data files
+	slug := g.win.Label()
+	var title, description string
+	if year, week, ok := g.win.ISOWeek(); ok {
+		title = fmt.Sprintf("Week %d - %s", week, g.win.Start.Format("January 2006"))
+		description = fmt.Sprintf("Cloud Native Newsletter Week %d %d: Kubernetes ecosystem releases and news.", week, year)
+	} else {
+		last := g.win.End.Add(-time.Second)
+		title = fmt.Sprintf("%s - %s", g.win.Start.Format("Jan 2"), last.Format("Jan 2, 2006"))
+		description = fmt.Sprintf("Cloud Native Newsletter %s to %s: Kubernetes ecosystem releases and news.", g.win.Start.Format("2006-01-02"), last.Format("2006-01-02"))
+	}
+	filename := slug + ".md"
 
 	metadata := models.DraftMetadata{
 		Title:       title,
-		Date:        g.weekStart.Format("2006-01-02"),
+		Date:        g.win.Start.Format("2006-01-02"),
 		Draft:       false,
 		Summary:     g.generateSummary(newsletter),
-		Description: fmt.Sprintf("Cloud Native Newsletter Week %d %d: Kubernetes ecosystem releases and news.", g.week, g.year),
+		Description: description,
 		Keywords:    []string{"Cloud Native Newsletter", "Kubernetes Releases", "CNCF Projects"},
 		Highlights:  g.extractHighlights(newsletter),
 	}
@@ -238,7 +238,16 @@ func (g *BackfillDraftGenerator) GenerateDraft(newsletter *models.Newsletter) (s
 	// Add article link
 	contentWithLink := newsletter.Content
 	contentWithLink = strings.TrimRight(contentWithLink, "\n\r\t ")
-	articlesURL := fmt.Sprintf("/newsletter/%d-week-%02d/articles/", g.year, g.week)
+	if section := ai.RenderBreakingSection(newsletter.Releases); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
+	if section := ai.RenderSecuritySection(newsletter.Releases, newsletter.Advisories); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
+	if section := ai.RenderLandscapeSection(newsletter.LandscapeChanges); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
+	articlesURL := fmt.Sprintf("/newsletter/%s/articles/", slug)
 	contentWithLink += fmt.Sprintf("\n\n📚 **[View all articles from this week →](%s)**\n", articlesURL)
 
 	content := fmt.Sprintf("---\n%s---\n\n%s", string(frontmatter), contentWithLink)
@@ -13,6 +13,9 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/mfahlandt/lwcn/internal/config"
 	"github.com/mfahlandt/lwcn/internal/github"
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/state"
+	"github.com/mfahlandt/lwcn/internal/window"
 )
 
 func main() {
@@ -22,8 +25,19 @@ func main() {
 	configPath := flag.String("config", "config/repositories.yaml", "Path to repositories config")
 	outputDir := flag.String("output", "data", "Output directory for releases")
 	collectStats := flag.Bool("stats", true, "Also collect neutral repo activity stats (commits, merged PRs)")
+	collectAdvisories := flag.Bool("advisories", true, "Also collect published repository security advisories (GHSA)")
+	statePath := flag.String("state", state.DefaultPath, "Path to the crawl state file (empty disables incremental crawling)")
+	workers := flag.Int("workers", github.DefaultWorkers, "Number of concurrent requests (repositories for REST, batches for GraphQL)")
+	useGraphQL := flag.Bool("graphql", true, "Use batched GraphQL queries for releases and stats (false: one REST call per repo)")
+	windowFlags := window.RegisterFlags()
 	flag.Parse()
 
+	win, err := windowFlags.Resolve(time.Now())
+	if err != nil {
+		log.Fatalf("Invalid crawl window: %v", err)
+	}
+	log.Printf("Crawl window: %s (%s)", win, win.Label())
+
 	// Get GitHub token from environment
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
@@ -37,15 +51,33 @@ func main() {
 
 	ctx := context.Background()
 	client := github.NewClient(token)
+	client.SetWorkers(*workers)
+	client.UseGraphQL(*useGraphQL)
 
 	log.Printf("Fetching releases from %d repositories...", len(cfg.Repositories))
 
-	releases, err := client.FetchAllReleases(ctx, cfg.Repositories)
+	// Explicit windows (backfills) are exact; the crawl state only drives live runs
+	var st *state.Store
+	if *statePath != "" && !windowFlags.Explicit() {
+		st, err = state.Load(*statePath)
+		if err != nil {
+			log.Fatalf("Failed to load crawl state: %v", err)
+		}
+		st.BeginEdition(win.Label())
+	}
+
+	var releases []models.Release
+	var skipped []github.SkippedRepo
+	if st != nil {
+		releases, skipped, err = client.FetchNewReleases(ctx, cfg.Repositories, st, win.Start, win.End)
+	} else {
+		releases, skipped, err = client.FetchReleasesInRange(ctx, cfg.Repositories, win.Start, win.End)
+	}
 	if err != nil {
 		log.Fatalf("Failed to fetch releases: %v", err)
 	}
 
-	log.Printf("Found %d releases from last 7 days", len(releases))
+	log.Printf("Found %d new releases (%d repositories skipped)", len(releases), len(skipped))
 
 	// Group releases by category for logging
 	categoryCount := make(map[string]int)
@@ -60,7 +92,7 @@ func main() {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	filename := fmt.Sprintf("releases-%s.json", time.Now().Format("2006-01-02"))
+	filename := fmt.Sprintf("releases-%s.json", win.Label())
 	outputPath := filepath.Join(*outputDir, filename)
 
 	data, err := json.MarshalIndent(releases, "", "  ")
@@ -74,13 +106,19 @@ func main() {
 
 	log.Printf("Releases saved to %s", outputPath)
 
+	// Only advance the cursors once the releases are safely on disk
+	if st != nil {
+		if err := st.Save(); err != nil {
+			log.Fatalf("Failed to save crawl state: %v", err)
+		}
+		log.Printf("Crawl state saved to %s", *statePath)
+	}
+
 	// --- Neutral activity stats (commits, merged PRs) for "Numbers of the Week" ---
 	if *collectStats {
-		end := time.Now()
-		start := end.AddDate(0, 0, -7)
-		stats := client.FetchAllStats(ctx, cfg.Repositories, start, end)
+		stats := client.FetchAllStats(ctx, cfg.Repositories, win.Start, win.End)
 
-		statsFile := fmt.Sprintf("stats-%s.json", time.Now().Format("2006-01-02"))
+		statsFile := fmt.Sprintf("stats-%s.json", win.Label())
 		statsPath := filepath.Join(*outputDir, statsFile)
 		if sdata, err := json.MarshalIndent(stats, "", "  "); err == nil {
 			if err := os.WriteFile(statsPath, sdata, 0644); err != nil {
@@ -90,4 +128,23 @@ func main() {
 			}
 		}
 	}
+
+	// --- Published security advisories for the security section ---
+	if *collectAdvisories {
+		advisories, skippedAdvisories, err := client.FetchAllAdvisories(ctx, cfg.Repositories, win.Start, win.End)
+		if err != nil {
+			log.Printf("Failed to fetch advisories: %v", err)
+			return
+		}
+
+		advisoriesFile := fmt.Sprintf("advisories-%s.json", win.Label())
+		advisoriesPath := filepath.Join(*outputDir, advisoriesFile)
+		if adata, err := json.MarshalIndent(advisories, "", "  "); err == nil {
+			if err := os.WriteFile(advisoriesPath, adata, 0644); err != nil {
+				log.Printf("Failed to write advisories: %v", err)
+			} else {
+				log.Printf("Advisories saved to %s (%d advisories, %d repositories skipped)", advisoriesPath, len(advisories), len(skippedAdvisories))
+			}
+		}
+	}
 }
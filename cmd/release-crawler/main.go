@@ -13,13 +13,30 @@ import (
 	"github.com/mfahlandt/lwcn/internal/config"
 	"github.com/mfahlandt/lwcn/internal/models"
 	"github.com/mfahlandt/lwcn/internal/news"
+	"github.com/mfahlandt/lwcn/internal/state"
+	"github.com/mfahlandt/lwcn/internal/window"
 )
 
 func main() {
 	configPath := flag.String("config", "config/news-sources.yaml", "Path to news sources config")
+	reposPath := flag.String("repos", "config/repositories.yaml", "Path to repositories config; tracked project names raise the relevance score")
 	outputDir := flag.String("output", "data", "Output directory for news")
+	statePath := flag.String("state", state.DefaultPath, "Path to the crawl state file (empty disables incremental crawling)")
+	feedWorkers := flag.Int("feed-workers", news.DefaultFeedWorkers, "Number of RSS feeds fetched concurrently")
+	feedTimeout := flag.Duration("feed-timeout", news.DefaultFeedTimeout, "Timeout for fetching a single RSS feed")
+	resolveRedirects := flag.Bool("resolve-redirects", true, "Follow redirects of news URLs when merging duplicate stories across sources")
+	extract := flag.Bool("extract", false, "Fetch each relevant news item's article and store an excerpt and word count")
+	extractCache := flag.String("extract-cache", news.DefaultArticleCacheDir, "Directory caching extracted articles between runs")
+	maxFailed := flag.Float64("max-failed-sources", 0.5, "Fail the crawl when more than this fraction of feeds and scrape sources fail (1 disables)")
+	windowFlags := window.RegisterFlags()
 	flag.Parse()
 
+	win, err := windowFlags.Resolve(time.Now())
+	if err != nil {
+		log.Fatalf("Invalid crawl window: %v", err)
+	}
+	log.Printf("Crawl window: %s (%s)", win, win.Label())
+
 	cfg, err := config.LoadNewsSources(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
@@ -28,9 +45,34 @@ func main() {
 	ctx := context.Background()
 	var allNews []models.NewsItem
 
+	// Failure counts continue from the previous report
+	healthFile := fmt.Sprintf("feed-health-%s.json", win.Label())
+	var previousHealth []models.FeedHealth
+	if path := previousHealthFile(*outputDir, win); path != "" {
+		previousHealth, err = news.LoadFeedHealth(path)
+		if err != nil {
+			log.Printf("Ignoring previous feed health: %v", err)
+		}
+	}
+	health := news.NewHealthTracker(previousHealth)
+
 	// RSS Feeds
 	log.Printf("Fetching %d RSS feeds...", len(cfg.RSSFeeds))
 	rssClient := news.NewRSSClient()
+	rssClient.UseWindow(win)
+	rssClient.SetWorkers(*feedWorkers)
+	rssClient.SetFeedTimeout(*feedTimeout)
+	rssClient.UseHealth(health)
+	// Explicit windows (backfills) are exact; the crawl state only drives live runs
+	var st *state.Store
+	if *statePath != "" && !windowFlags.Explicit() {
+		st, err = state.Load(*statePath)
+		if err != nil {
+			log.Fatalf("Failed to load crawl state: %v", err)
+		}
+		st.BeginEdition(win.Label())
+		rssClient.UseState(st)
+	}
 	rssNews, err := rssClient.FetchAllFeeds(ctx, cfg.RSSFeeds)
 	if err == nil {
 		allNews = append(allNews, rssNews...)
@@ -40,8 +82,10 @@ func main() {
 	// Scraping
 	log.Printf("Scraping %d sources...", len(cfg.ScrapeSources))
 	scraper := news.NewScraper()
+	scraper.UseWindow(win)
 	for _, source := range cfg.ScrapeSources {
-		items, err := scraper.ScrapeHeise(ctx, source)
+		items, err := scraper.Scrape(ctx, source)
+		health.Record(news.KindScrape, source.Name, source.URL, len(items), false, err)
 		if err != nil {
 			log.Printf("Scraping %s failed: %v", source.Name, err)
 			continue
@@ -54,6 +98,7 @@ func main() {
 	if cfg.HackerNews.Enabled {
 		log.Println("Searching Hacker News...")
 		hnClient := news.NewHackerNewsClient()
+		hnClient.UseWindow(win)
 		hnNews, err := hnClient.Search(ctx, cfg.HackerNews.Keywords)
 		if err == nil {
 			allNews = append(allNews, hnNews...)
@@ -61,13 +106,44 @@ func main() {
 		}
 	}
 
+	// The same story often comes from several sources; merge them into one
+	// item that lists every source
+	deduplicator := news.NewDeduplicator()
+	deduplicator.FollowRedirects(*resolveRedirects)
+	crawled := len(allNews)
+	allNews = deduplicator.Deduplicate(ctx, allNews)
+	log.Printf("Merged %d duplicate news items across sources", crawled-len(allNews))
+
+	// Score relevance; the cutoff is applied when the newsletter is generated
+	// so the crawled data stays complete
+	scorer := news.NewScorer(cfg.Scoring)
+	if repoCfg, err := config.LoadRepositories(*reposPath); err == nil {
+		scorer.UseProjects(repoCfg.Repositories)
+	} else {
+		log.Printf("Scoring without tracked project names: %v", err)
+	}
+	if scorer.Enabled() {
+		scorer.Score(allNews)
+		relevant := news.SelectRelevant(allNews, cfg.Scoring.MinScore, 0)
+		log.Printf("Scored %d news items, %d at or above the cutoff of %.1f", len(allNews), len(relevant), cfg.Scoring.MinScore)
+	}
+
+	// Article text for the items that can make it into the newsletter
+	if *extract {
+		var relevant func(models.NewsItem) bool
+		if scorer.Enabled() {
+			relevant = func(item models.NewsItem) bool { return item.Score >= cfg.Scoring.MinScore }
+		}
+		news.NewExtractor(*extractCache).Extract(ctx, allNews, relevant)
+	}
+
 	log.Printf("Total: %d news items", len(allNews))
 
 	if err := os.MkdirAll(*outputDir, 0755); err != nil {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	filename := fmt.Sprintf("news-%s.json", time.Now().Format("2006-01-02"))
+	filename := fmt.Sprintf("news-%s.json", win.Label())
 	outputPath := filepath.Join(*outputDir, filename)
 
 	data, err := json.MarshalIndent(allNews, "", "  ")
@@ -80,4 +156,59 @@ func main() {
 	}
 
 	log.Printf("News saved to %s", outputPath)
+
+	report := health.Report()
+	healthPath := filepath.Join(*outputDir, healthFile)
+	if hdata, err := json.MarshalIndent(report, "", "  "); err == nil {
+		if err := os.WriteFile(healthPath, hdata, 0644); err != nil {
+			log.Printf("Failed to write feed health: %v", err)
+		} else {
+			log.Printf("Feed health saved to %s", healthPath)
+		}
+	}
+	printHealth(report)
+
+	// Leave the cursors untouched so a re-run after fixing the sources
+	// crawls the same items
+	if failed := health.Failed(); len(report) > 0 && float64(failed) > *maxFailed*float64(len(report)) {
+		log.Fatalf("%d of %d news sources failed, more than the allowed %.0f%%", failed, len(report), *maxFailed*100)
+	}
+
+	// Only advance the cursors once the news items are safely on disk
+	if st != nil {
+		if err := st.Save(); err != nil {
+			log.Fatalf("Failed to save crawl state: %v", err)
+		}
+		log.Printf("Crawl state saved to %s", *statePath)
+	}
+}
+
+func printHealth(report []models.FeedHealth) {
+	log.Println("Source health:")
+	for _, h := range report {
+		line := fmt.Sprintf("  %-6s %-13s %-28s %3d items", h.Kind, h.Status, h.Name, h.ItemCount)
+		if h.Status == models.FeedFailed {
+			last := "never"
+			if !h.LastSuccessAt.IsZero() {
+				last = h.LastSuccessAt.Format("2006-01-02")
+			}
+			line += fmt.Sprintf(" - %d consecutive failures, last success %s: %s", h.ConsecutiveFailures, last, h.Error)
+		}
+		log.Print(line)
+	}
+}
+
+// previousHealthFile returns the latest feed health report in dir for a
+// window ending no later than current, other than the one for current
+// itself, so re-running a crawl for the same edition does not count its
+// failures twice.
+func previousHealthFile(dir string, current window.Window) string {
+	var latest string
+	for _, f := range window.Files(dir, "feed-health-") {
+		if f.Window.End.After(current.End) || f.Window.Label() == current.Label() {
+			continue
+		}
+		latest = f.Path
+	}
+	return latest
 }
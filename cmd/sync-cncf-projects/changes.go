@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// diffProjects compares the CNCF projects of the previous repositories config
+// with the new one. Projects are compared by name, one entry per project, so
+// extra repositories of a project do not show up as projects of their own.
+// Archived projects are reported as archived rather than removed, and a
+// project that is still in the landscape (excluded by an override) is not
+// reported as removed at all.
+func diffProjects(previous, current, archived, landscape []models.Repository) models.LandscapeChanges {
+	prev := projectsByName(previous)
+	curr := projectsByName(current)
+	arch := projectsByName(archived)
+	listed := projectsByName(landscape)
+
+	// Repositories of previous projects detect renames in the landscape
+	prevRepos := make(map[string]bool)
+	for _, r := range prev {
+		if r.Owner != "" {
+			prevRepos[repoKey(r)] = true
+		}
+	}
+
+	changes := models.LandscapeChanges{SyncedAt: time.Now().UTC()}
+
+	for key, r := range curr {
+		old, ok := prev[key]
+		if !ok {
+			if prevRepos[repoKey(r)] {
+				log.Printf("Project %s was renamed in the landscape, not reporting it as joined", r.Name)
+				continue
+			}
+			changes.Joined = append(changes.Joined, projectChange(r, "", r.CNCFStatus))
+			continue
+		}
+		from, to := strings.ToLower(old.CNCFStatus), strings.ToLower(r.CNCFStatus)
+		if from == to {
+			continue
+		}
+		if statusOrder(to) < statusOrder(from) {
+			changes.Promoted = append(changes.Promoted, projectChange(r, from, to))
+		} else {
+			log.Printf("Project %s moved from %s to %s, not reporting it as a promotion", r.Name, from, to)
+		}
+	}
+
+	currRepos := make(map[string]bool)
+	for _, r := range curr {
+		if r.Owner != "" {
+			currRepos[repoKey(r)] = true
+		}
+	}
+
+	for key, old := range prev {
+		if _, ok := curr[key]; ok {
+			continue
+		}
+		if a, ok := arch[key]; ok {
+			changes.Archived = append(changes.Archived, projectChange(a, old.CNCFStatus, a.CNCFStatus))
+			continue
+		}
+		if _, ok := listed[key]; ok || currRepos[repoKey(old)] {
+			continue
+		}
+		changes.Removed = append(changes.Removed, projectChange(old, old.CNCFStatus, ""))
+	}
+
+	for _, list := range [][]models.ProjectChange{changes.Joined, changes.Promoted, changes.Archived, changes.Removed} {
+		sort.Slice(list, func(i, j int) bool {
+			return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+		})
+	}
+	return changes
+}
+
+// projectsByName indexes the CNCF projects of repos by lowercased project
+// name. Entries of extra repositories ("Argo (argo-workflows)") count for
+// their project; the project's own entry wins over them.
+func projectsByName(repos []models.Repository) map[string]models.Repository {
+	projects := make(map[string]models.Repository)
+	for _, r := range repos {
+		if r.CNCFStatus == "" {
+			continue
+		}
+		name := projectName(r)
+		key := strings.ToLower(name)
+		if existing, ok := projects[key]; ok && existing.Name == name {
+			continue
+		}
+		r.Name = name
+		projects[key] = r
+	}
+	return projects
+}
+
+// projectName strips the " (repo)" suffix the sync adds to the names of a
+// project's extra repositories.
+func projectName(r models.Repository) string {
+	suffix := fmt.Sprintf(" (%s)", r.Repo)
+	if r.Repo != "" && strings.HasSuffix(r.Name, suffix) {
+		return strings.TrimSuffix(r.Name, suffix)
+	}
+	return r.Name
+}
+
+func repoKey(r models.Repository) string {
+	return strings.ToLower(r.Owner + "/" + r.Repo)
+}
+
+func projectChange(r models.Repository, from, to string) models.ProjectChange {
+	return models.ProjectChange{
+		Name:     r.Name,
+		Owner:    r.Owner,
+		Repo:     r.Repo,
+		Category: r.Category,
+		From:     strings.ToLower(from),
+		To:       strings.ToLower(to),
+	}
+}
+
+func printChanges(changes models.LandscapeChanges) {
+	if changes.Empty() {
+		log.Println("No CNCF project changes this week")
+		return
+	}
+	log.Println("CNCF project changes this week:")
+	for _, c := range changes.Joined {
+		log.Printf("  joined   : %s (%s)", c.Name, c.To)
+	}
+	for _, c := range changes.Promoted {
+		log.Printf("  promoted : %s (%s -> %s)", c.Name, c.From, c.To)
+	}
+	for _, c := range changes.Archived {
+		log.Printf("  archived : %s (was %s)", c.Name, c.From)
+	}
+	for _, c := range changes.Removed {
+		log.Printf("  removed  : %s (was %s)", c.Name, c.From)
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mfahlandt/lwcn/internal/cncf"
 	"github.com/mfahlandt/lwcn/internal/config"
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/window"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,10 +23,20 @@ func main() {
 	outputPath := flag.String("output", "config/repositories.yaml", "Path to write the merged repositories config")
 	additionalPath := flag.String("additional", "config/additional-repos.yaml", "Path to additional repositories config")
 	dryRun := flag.Bool("dry-run", false, "Print the result without writing to file")
+	extraRepos := flag.Bool("landscape-additional-repos", false, "Also track the additional repositories each project lists in the landscape")
+	inputPath := flag.String("input", "", "Read landscape data from a local file (landscape page HTML, full.json or landscape.yml) instead of the network")
+	landscapeURL := flag.String("landscape-url", cncf.LandscapeURL, "Base URL of the CNCF landscape")
+	changesDir := flag.String("changes-dir", "data", "Directory for the landscape-changes-*.json diff against the output before the week's first sync, kept as landscape-baseline-*.yaml (empty disables it)")
 	flag.Parse()
 
 	// Step 1: Fetch CNCF projects from Landscape API
-	cncfRepos, err := cncf.FetchCNCFProjects()
+	fetcher := cncf.NewFetcher()
+	fetcher.UseBaseURL(*landscapeURL)
+	fetcher.IncludeAdditionalRepos(*extraRepos)
+	if *inputPath != "" {
+		fetcher.UseFile(*inputPath)
+	}
+	cncfRepos, err := fetcher.Fetch(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to fetch CNCF projects: %v", err)
 	}
@@ -30,26 +45,59 @@ func main() {
 
 	// Step 2: Load additional repositories (if file exists)
 	var additionalRepos []models.Repository
+	var overrides []models.RepositoryOverride
 	if _, err := os.Stat(*additionalPath); err == nil {
 		additionalCfg, err := config.LoadAdditionalRepositories(*additionalPath)
 		if err != nil {
 			log.Fatalf("Failed to load additional repositories from %s: %v", *additionalPath, err)
 		}
 		additionalRepos = additionalCfg.AdditionalRepositories
-		log.Printf("Loaded %d additional repositories from %s", len(additionalRepos), *additionalPath)
+		overrides = additionalCfg.Overrides
+		log.Printf("Loaded %d additional repositories and %d overrides from %s", len(additionalRepos), len(overrides), *additionalPath)
 	} else {
 		log.Printf("No additional repositories file found at %s, skipping", *additionalPath)
 	}
 
-	// Step 3: Merge - CNCF projects take priority, additional repos fill gaps
+	// Keep the landscape's own list: excluding a project by override does
+	// not remove it from the CNCF
+	landscapeRepos := cncfRepos
+
+	// Step 3: Apply overrides to the synced entries (fix mappings, exclude, add repos)
+	cncfRepos, err = applyOverrides(cncfRepos, overrides)
+	if err != nil {
+		log.Fatalf("Failed to apply overrides from %s: %v", *additionalPath, err)
+	}
+
+	// Step 4: Merge - CNCF projects take priority, additional repos fill gaps
 	merged := mergeRepositories(cncfRepos, additionalRepos)
 
 	log.Printf("Merged result: %d total repositories", len(merged))
 
-	// Step 4: Print summary
+	// Step 5: Print summary
 	printSummary(merged)
 
-	// Step 5: Write output
+	// Step 6: Diff the CNCF projects against the week's baseline: the output
+	// before the week's first sync. Later syncs in the same week then still
+	// report every change of the week, not only those since the last sync.
+	label := window.LastCompletedWeek(time.Now()).Label()
+	var changes *models.LandscapeChanges
+	var baselinePath string
+	if *changesDir != "" {
+		previousPath := filepath.Join(*changesDir, fmt.Sprintf("landscape-baseline-%s.yaml", label))
+		if _, err := os.Stat(previousPath); err != nil {
+			// First sync of the week: the previous output becomes the baseline
+			baselinePath, previousPath = previousPath, *outputPath
+		}
+		if previous, err := config.LoadRepositories(previousPath); err == nil {
+			diff := diffProjects(previous.Repositories, merged, fetcher.Archived(), landscapeRepos)
+			changes = &diff
+			printChanges(diff)
+		} else {
+			log.Printf("No previous repositories config to diff against (%v), skipping project changes", err)
+		}
+	}
+
+	// Step 7: Write output
 	repoCfg := models.RepositoryConfig{
 		Repositories: merged,
 	}
@@ -65,26 +113,76 @@ func main() {
 		return
 	}
 
+	if changes != nil && baselinePath != "" {
+		if err := saveBaseline(*outputPath, baselinePath); err != nil {
+			log.Fatalf("Failed to save the project changes baseline: %v", err)
+		}
+	}
+
 	if err := os.WriteFile(*outputPath, data, 0644); err != nil {
 		log.Fatalf("Failed to write output: %v", err)
 	}
 
 	log.Printf("Successfully wrote %d repositories to %s", len(merged), *outputPath)
+
+	// Written every run, also without changes, so the newsletter never picks
+	// up the changes of an earlier week
+	if changes != nil {
+		if err := writeChanges(*changesDir, label, *changes); err != nil {
+			log.Fatalf("Failed to write project changes: %v", err)
+		}
+	}
+}
+
+// writeChanges saves the project changes as landscape-changes-<label>.json,
+// labelled with the week the newsletter covers.
+func writeChanges(dir, label string, changes models.LandscapeChanges) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("landscape-changes-%s.json", label))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	log.Printf("Project changes saved to %s", path)
+	return nil
+}
+
+// saveBaseline copies the repositories config at src, before it is
+// overwritten, to the baseline path of the week.
+func saveBaseline(src, path string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	log.Printf("Saved %s as the project changes baseline of the week to %s", src, path)
+	return nil
 }
 
 // mergeRepositories merges CNCF repos with additional repos.
-// CNCF repos take priority. Additional repos are only added if not already present.
+// CNCF repos take priority. Additional repos are only added if not already
+// present; for repos that are, only their crawl options are applied.
 func mergeRepositories(cncfRepos, additionalRepos []models.Repository) []models.Repository {
-	seen := make(map[string]bool)
+	index := make(map[string]int)
 	var result []models.Repository
 
 	// Add all CNCF repos first
 	for _, r := range cncfRepos {
 		key := strings.ToLower(r.Owner + "/" + r.Repo)
-		if seen[key] {
+		if _, ok := index[key]; ok {
 			continue
 		}
-		seen[key] = true
+		index[key] = len(result)
 		result = append(result, r)
 	}
 
@@ -92,10 +190,11 @@ func mergeRepositories(cncfRepos, additionalRepos []models.Repository) []models.
 	added := 0
 	for _, r := range additionalRepos {
 		key := strings.ToLower(r.Owner + "/" + r.Repo)
-		if seen[key] {
+		if i, ok := index[key]; ok {
+			applyCrawlOptions(&result[i], r)
 			continue
 		}
-		seen[key] = true
+		index[key] = len(result)
 		result = append(result, r)
 		added++
 	}
@@ -116,6 +215,26 @@ func mergeRepositories(cncfRepos, additionalRepos []models.Repository) []models.
 	return result
 }
 
+// applyCrawlOptions copies the crawl settings of an additional-repos entry
+// onto a CNCF-synced entry for the same repository.
+func applyCrawlOptions(dst *models.Repository, src models.Repository) {
+	if src.Source != "" {
+		dst.Source = src.Source
+	}
+	if src.Grouping != "" {
+		dst.Grouping = src.Grouping
+	}
+	if len(src.IncludeTags) > 0 {
+		dst.IncludeTags = src.IncludeTags
+	}
+	if len(src.ExcludeTags) > 0 {
+		dst.ExcludeTags = src.ExcludeTags
+	}
+	if src.TrackPrereleases != nil {
+		dst.TrackPrereleases = src.TrackPrereleases
+	}
+}
+
 func statusOrder(status string) int {
 	switch strings.ToLower(status) {
 	case "graduated":
@@ -218,6 +337,21 @@ func marshalRepositoriesYAML(cfg models.RepositoryConfig) ([]byte, error) {
 		if r.CNCFStatus != "" {
 			entry["cncf_status"] = r.CNCFStatus
 		}
+		if r.Source != "" {
+			entry["source"] = r.Source
+		}
+		if r.Grouping != "" {
+			entry["grouping"] = r.Grouping
+		}
+		if len(r.IncludeTags) > 0 {
+			entry["include_tags"] = r.IncludeTags
+		}
+		if len(r.ExcludeTags) > 0 {
+			entry["exclude_tags"] = r.ExcludeTags
+		}
+		if r.TrackPrereleases != nil {
+			entry["track_prereleases"] = *r.TrackPrereleases
+		}
 
 		entryData, err := yaml.Marshal(entry)
 		if err != nil {
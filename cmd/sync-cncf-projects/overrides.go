@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// applyOverrides applies the overrides from additional-repos.yaml to the
+// CNCF-synced repos, in order. Overrides that match nothing are logged so
+// stale entries are noticed after landscape renames.
+func applyOverrides(repos []models.Repository, overrides []models.RepositoryOverride) ([]models.Repository, error) {
+	for i, o := range overrides {
+		if o.Project == "" && o.Repo == "" {
+			return nil, fmt.Errorf("override %d: project or repo is required", i+1)
+		}
+
+		var result []models.Repository
+		matched := 0
+		for _, r := range repos {
+			if !overrideMatches(o, r) {
+				result = append(result, r)
+				continue
+			}
+			matched++
+
+			if o.Exclude {
+				log.Printf("Override: excluding %s (%s/%s)", r.Name, r.Owner, r.Repo)
+				continue
+			}
+			if o.Set != nil {
+				patchRepository(&r, *o.Set)
+			}
+			result = append(result, r)
+
+			for _, extra := range o.ExtraRepos {
+				if extra.Owner == "" || extra.Repo == "" {
+					return nil, fmt.Errorf("override %d: extra_repos entries need owner and repo", i+1)
+				}
+				if extra.Name == "" {
+					extra.Name = r.Name
+				}
+				if extra.Category == "" {
+					extra.Category = r.Category
+				}
+				if extra.CNCFStatus == "" {
+					extra.CNCFStatus = r.CNCFStatus
+				}
+				log.Printf("Override: adding %s/%s for %s", extra.Owner, extra.Repo, r.Name)
+				result = append(result, extra)
+			}
+		}
+
+		if matched == 0 {
+			log.Printf("Warning: override %d (%s) matched no CNCF project", i+1, describeOverride(o))
+		}
+		repos = result
+	}
+
+	return repos, nil
+}
+
+// overrideMatches reports whether o selects r. With both project and repo
+// set, both must match.
+func overrideMatches(o models.RepositoryOverride, r models.Repository) bool {
+	if o.Project != "" && !strings.EqualFold(o.Project, r.Name) {
+		return false
+	}
+	if o.Repo != "" && !strings.EqualFold(o.Repo, r.Owner+"/"+r.Repo) {
+		return false
+	}
+	return true
+}
+
+func describeOverride(o models.RepositoryOverride) string {
+	if o.Project != "" && o.Repo != "" {
+		return o.Project + ", " + o.Repo
+	}
+	return o.Project + o.Repo
+}
+
+// patchRepository copies every field that is set in patch onto r.
+func patchRepository(r *models.Repository, patch models.Repository) {
+	if patch.Owner != "" {
+		r.Owner = patch.Owner
+	}
+	if patch.Repo != "" {
+		r.Repo = patch.Repo
+	}
+	if patch.Name != "" {
+		r.Name = patch.Name
+	}
+	if patch.Category != "" {
+		r.Category = patch.Category
+	}
+	if patch.CNCFStatus != "" {
+		r.CNCFStatus = patch.CNCFStatus
+	}
+	applyCrawlOptions(r, patch)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
 	"gopkg.in/yaml.v3"
 )
 
@@ -75,6 +76,16 @@ func (g *DraftGenerator) GenerateDraft(newsletter *models.Newsletter) (string, e
 	articlesLinkRe := regexp.MustCompile(`(?m)\n*📚\s*\*{0,2}\[View all articles[^\]]*\]\([^)]*\)\*{0,2}\s*\n*`)
 	contentWithLink = articlesLinkRe.ReplaceAllString(contentWithLink, "")
 	contentWithLink = strings.TrimRight(contentWithLink, "\n\r\t ")
+	// Deterministic sections built from the release data, not the model
+	if section := RenderBreakingSection(newsletter.Releases); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
+	if section := RenderSecuritySection(newsletter.Releases, newsletter.Advisories); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
+	if section := RenderLandscapeSection(newsletter.LandscapeChanges); section != "" {
+		contentWithLink += "\n\n" + strings.TrimRight(section, "\n")
+	}
 	// Always use the full absolute URL with domain
 	articlesURL := fmt.Sprintf("https://lwcn.dev/newsletter/%d-week-%02d/articles/", year, week)
 	contentWithLink += fmt.Sprintf("\n\n📚 **[View all articles from this week →](%s)**\n", articlesURL)
@@ -154,7 +165,17 @@ A complete list of all cloud native articles and news from this week.
 			if title == "" {
 				title = "Untitled"
 			}
-			content.WriteString(fmt.Sprintf("- [%s](%s)\n", title, item.URL))
+			content.WriteString(fmt.Sprintf("- [%s](%s)", title, item.URL))
+			var also []string
+			for _, a := range item.Attributions {
+				if a.Source != item.Source {
+					also = append(also, fmt.Sprintf("[%s](%s)", a.Source, a.URL))
+				}
+			}
+			if len(also) > 0 {
+				content.WriteString(" (also via " + strings.Join(also, ", ") + ")")
+			}
+			content.WriteString("\n")
 		}
 		content.WriteString("\n")
 	}
@@ -174,46 +195,50 @@ func generateSummary(newsletter *models.Newsletter) string {
 	releaseCount := len(newsletter.Releases)
 	newsCount := len(newsletter.NewsItems)
 
-	majorReleases := []string{}
+	notableReleases := []string{}
 	for _, r := range newsletter.Releases {
-		if isMajorRelease(r.TagName) {
-			majorReleases = append(majorReleases, fmt.Sprintf("%s %s", r.RepoName, r.TagName))
+		if isNotableRelease(r) {
+			notableReleases = append(notableReleases, fmt.Sprintf("%s %s", r.RepoName, r.TagName))
 		}
 	}
 
 	summary := fmt.Sprintf("This week: %d releases, %d news items.", releaseCount, newsCount)
-	if len(majorReleases) > 0 {
-		summary += fmt.Sprintf(" Notable: %s.", strings.Join(majorReleases[:min(3, len(majorReleases))], ", "))
+	if len(notableReleases) > 0 {
+		summary += fmt.Sprintf(" Notable: %s.", strings.Join(notableReleases[:min(3, len(notableReleases))], ", "))
 	}
 
 	return summary
 }
 
+// extractHighlights lists up to five projects, those with notable (major or
+// minor) releases first, in release order.
 func extractHighlights(newsletter *models.Newsletter) []string {
-	highlights := make(map[string]bool)
+	seen := make(map[string]bool)
+	result := []string{}
+	add := func(name string) {
+		if !seen[name] && len(result) < 5 {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
 
 	for _, r := range newsletter.Releases {
-		highlights[r.RepoName] = true
+		if isNotableRelease(r) {
+			add(r.RepoName)
+		}
 	}
-
-	result := []string{}
-	for name := range highlights {
-		result = append(result, name)
-		if len(result) >= 5 {
-			break
+	for _, r := range newsletter.Releases {
+		if !isPreRelease(r) {
+			add(r.RepoName)
 		}
 	}
 
 	return result
 }
 
-func isMajorRelease(tag string) bool {
-	tag = strings.TrimPrefix(tag, "v")
-	parts := strings.Split(tag, ".")
-	if len(parts) >= 2 {
-		return parts[1] == "0" && (len(parts) < 3 || parts[2] == "0")
-	}
-	return false
+// isNotableRelease reports whether r is a stable major or minor release.
+func isNotableRelease(r models.Release) bool {
+	return !isPreRelease(r) && version.IsNotable(r)
 }
 
 func min(a, b int) int {
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// DefaultAPITimeout is the default timeout for a single model call
+const DefaultAPITimeout = 5 * time.Minute
+
+// ErrNoContent is returned by a TextModel when the backend answered but
+// produced no usable text.
+var ErrNoContent = errors.New("no content generated")
+
+// TextModel is the minimal contract an LLM backend has to fulfil: take one
+// prompt, return one completion. Everything newsletter-specific (prompt
+// building, JSON parsing, post-processing) lives in Generator, so backends
+// stay thin and a fake model is enough to exercise the whole pipeline.
+type TextModel interface {
+	// Name is a human-readable backend name used in log output.
+	Name() string
+	// GenerateText sends a single prompt and returns the raw completion.
+	GenerateText(ctx context.Context, prompt string) (string, error)
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// Generator runs the LWCN prompt pipeline against any TextModel.
+type Generator struct {
+	model TextModel
+}
+
+func NewGenerator(model TextModel) *Generator {
+	return &Generator{model: model}
+}
+
+// Name returns the name of the underlying model backend.
+func (g *Generator) Name() string {
+	return g.model.Name()
+}
+
+func (g *Generator) Close() error {
+	return g.model.Close()
+}
+
+// generate applies DefaultAPITimeout to a single model call.
+func (g *Generator) generate(ctx context.Context, prompt string) (string, error) {
+	apiCtx, cancel := context.WithTimeout(ctx, DefaultAPITimeout)
+	defer cancel()
+
+	return g.model.GenerateText(apiCtx, prompt)
+}
+
+// SocialShorts bundles all three short-format posts produced in a single
+// model call. Consolidating into one request saves free-tier quota
+// (1 call instead of 3) and keeps all shorts consistent with each other.
+type SocialShorts struct {
+	LinkedInShort string `json:"linkedin_short"`
+	Tweet         string `json:"tweet"`
+	Bluesky       string `json:"bluesky"`
+}
+
+// GenerateSocialShorts produces the LinkedIn teaser, the tweet, and the
+// Bluesky post in ONE model call, all derived from the long LinkedIn article.
+// The model returns strict JSON which is parsed into SocialShorts.
+func (g *Generator) GenerateSocialShorts(ctx context.Context, longPost, newsletterURL string) (*SocialShorts, error) {
+	prompt := buildCombinedShortsPrompt(longPost, newsletterURL)
+	raw, err := g.generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate social shorts: %w", err)
+	}
+
+	jsonStr := extractJSON(raw)
+	var out SocialShorts
+	if err := json.Unmarshal([]byte(jsonStr), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse social shorts JSON: %w\nraw output:\n%s", err, raw)
+	}
+	out.LinkedInShort = strings.TrimSpace(out.LinkedInShort)
+	out.Tweet = strings.TrimSpace(out.Tweet)
+	out.Bluesky = strings.TrimSpace(out.Bluesky)
+	if out.LinkedInShort == "" || out.Tweet == "" || out.Bluesky == "" {
+		return nil, fmt.Errorf("social shorts JSON missing fields\nraw output:\n%s", raw)
+	}
+	return &out, nil
+}
+
+// extractJSON tolerates responses wrapped in markdown code fences or with
+// leading/trailing prose, and returns the first {...} block.
+func extractJSON(s string) string {
+	// Strip ```json ... ``` fences if present
+	fence := regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)```")
+	if m := fence.FindStringSubmatch(s); len(m) == 2 {
+		s = m[1]
+	}
+	// Take first balanced {...} block
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start >= 0 && end > start {
+		return s[start : end+1]
+	}
+	return strings.TrimSpace(s)
+}
+
+// GenerateLinkedInPost generates the long-form LinkedIn newsletter article.
+// This is the ONE "creative" call; the three short formats are produced from
+// its output in a single combined call via GenerateSocialShorts.
+func (g *Generator) GenerateLinkedInPost(ctx context.Context, releases []models.Release, news []models.NewsItem) (string, error) {
+	prompt := buildLinkedInPrompt(releases, news)
+
+	content, err := g.generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate LinkedIn post: %w", err)
+	}
+
+	return content, nil
+}
+
+// GenerateLinkedInShortPost is deprecated; use GenerateSocialShorts instead.
+// Kept only so external callers (tests, older scripts) keep compiling.
+func (g *Generator) GenerateLinkedInShortPost(ctx context.Context, longPost string, _ []models.Release, _ []models.NewsItem) (string, error) {
+	s, err := g.GenerateSocialShorts(ctx, longPost, "https://lwcn.dev/")
+	if err != nil {
+		return "", err
+	}
+	return s.LinkedInShort, nil
+}
+
+func (g *Generator) GenerateNewsletter(ctx context.Context, releases []models.Release, news []models.NewsItem, stats []models.RepoStats) (*models.Newsletter, error) {
+	prompt := buildPrompt(releases, news, stats)
+
+	content, err := g.generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	newsletter := &models.Newsletter{
+		Content:   content,
+		Releases:  releases,
+		NewsItems: news,
+	}
+
+	return newsletter, nil
+}
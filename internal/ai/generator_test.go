@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// fakeModel is a TextModel that records the prompts it gets and answers
+// with a fixed response.
+type fakeModel struct {
+	response string
+	err      error
+	prompts  []string
+	closed   bool
+}
+
+func (f *fakeModel) Name() string { return "fake" }
+
+func (f *fakeModel) GenerateText(ctx context.Context, prompt string) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	if _, ok := ctx.Deadline(); !ok {
+		return "", errors.New("model called without a deadline")
+	}
+	return f.response, f.err
+}
+
+func (f *fakeModel) Close() error {
+	f.closed = true
+	return nil
+}
+
+var (
+	testReleases = []models.Release{
+		{RepoOwner: "cilium", RepoName: "cilium", TagName: "v1.18.6", Category: "networking", URL: "https://github.com/cilium/cilium/releases/tag/v1.18.6", Body: "Publishes Helm charts to OCI registries."},
+		{RepoOwner: "envoyproxy", RepoName: "envoy", TagName: "v1.35.0-rc.1", Category: "networking", URL: "https://github.com/envoyproxy/envoy/releases/tag/v1.35.0-rc.1"},
+	}
+	testNews = []models.NewsItem{
+		{Source: "CNCF Blog", Title: "KubeCon schedule announced", URL: "https://www.cncf.io/blog/kubecon", Description: "The schedule is out."},
+		{Source: "Hacker News", Title: "Ask HN: Kubernetes at home?", URL: "https://news.ycombinator.com/item?id=1"},
+	}
+)
+
+func TestGenerateNewsletter(t *testing.T) {
+	model := &fakeModel{response: "## 👋 Welcome\nHello"}
+	g := NewGenerator(model)
+
+	nl, err := g.GenerateNewsletter(context.Background(), testReleases, testNews, []models.RepoStats{{RepoOwner: "cilium", RepoName: "cilium", Commits: 42}})
+	if err != nil {
+		t.Fatalf("GenerateNewsletter: %v", err)
+	}
+	if len(model.prompts) != 1 {
+		t.Fatalf("model called %d times, want 1", len(model.prompts))
+	}
+
+	prompt := model.prompts[0]
+	for _, want := range []string{
+		"cilium/cilium v1.18.6 (networking)",
+		"URL: https://github.com/cilium/cilium/releases/tag/v1.18.6",
+		"Publishes Helm charts to OCI registries.",
+		"[CNCF Blog] KubeCon schedule announced",
+		"cilium/cilium — 42 commits",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q", want)
+		}
+	}
+	if strings.Contains(prompt, "v1.35.0-rc.1") {
+		t.Error("prompt contains a pre-release")
+	}
+
+	if nl.Content != model.response {
+		t.Errorf("Content = %q, want the model response", nl.Content)
+	}
+	if len(nl.Releases) != len(testReleases) || len(nl.NewsItems) != len(testNews) {
+		t.Errorf("newsletter has %d releases and %d news items, want %d and %d", len(nl.Releases), len(nl.NewsItems), len(testReleases), len(testNews))
+	}
+}
+
+func TestGenerateNewsletterError(t *testing.T) {
+	model := &fakeModel{err: ErrNoContent}
+	_, err := NewGenerator(model).GenerateNewsletter(context.Background(), testReleases, testNews, nil)
+	if !errors.Is(err, ErrNoContent) {
+		t.Fatalf("err = %v, want ErrNoContent", err)
+	}
+}
+
+func TestGenerateLinkedInPost(t *testing.T) {
+	model := &fakeModel{response: "🚀 Cilium v1.18.6 released"}
+	post, err := NewGenerator(model).GenerateLinkedInPost(context.Background(), testReleases, testNews)
+	if err != nil {
+		t.Fatalf("GenerateLinkedInPost: %v", err)
+	}
+	if post != model.response {
+		t.Errorf("post = %q, want the model response", post)
+	}
+
+	prompt := model.prompts[0]
+	if !strings.Contains(prompt, "- cilium v1.18.6: Publishes Helm charts") {
+		t.Error("prompt does not list the release")
+	}
+	if !strings.Contains(prompt, "[CNCF Blog] KubeCon schedule announced") {
+		t.Error("prompt does not list the news item")
+	}
+	if strings.Contains(prompt, "Ask HN") {
+		t.Error("prompt contains Hacker News items")
+	}
+}
+
+func TestGenerateSocialShorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     *SocialShorts
+		wantErr  bool
+	}{
+		{
+			name:     "plain JSON",
+			response: `{"linkedin_short": " Short ", "tweet": "Tweet", "bluesky": "Skeet"}`,
+			want:     &SocialShorts{LinkedInShort: "Short", Tweet: "Tweet", Bluesky: "Skeet"},
+		},
+		{
+			name:     "fenced with prose",
+			response: "Here you go:\n```json\n{\"linkedin_short\": \"Short\", \"tweet\": \"Tweet\", \"bluesky\": \"Skeet\"}\n```\nEnjoy!",
+			want:     &SocialShorts{LinkedInShort: "Short", Tweet: "Tweet", Bluesky: "Skeet"},
+		},
+		{
+			name:     "missing field",
+			response: `{"linkedin_short": "Short", "tweet": "Tweet"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "not JSON",
+			response: "Sorry, I cannot help with that.",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &fakeModel{response: tt.response}
+			got, err := NewGenerator(model).GenerateSocialShorts(context.Background(), "Long article text", "https://lwcn.dev/newsletter/2026-week-41/")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateSocialShorts: %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+
+			prompt := model.prompts[0]
+			if !strings.Contains(prompt, "Long article text") || !strings.Contains(prompt, "https://lwcn.dev/newsletter/2026-week-41/") {
+				t.Error("prompt does not contain the long post and the newsletter URL")
+			}
+		})
+	}
+}
+
+func TestGeneratorClose(t *testing.T) {
+	model := &fakeModel{}
+	g := NewGenerator(model)
+	if g.Name() != "fake" {
+		t.Errorf("Name() = %q, want %q", g.Name(), "fake")
+	}
+	if err := g.Close(); err != nil || !model.closed {
+		t.Errorf("Close() = %v, model closed: %v", err, model.closed)
+	}
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultOpenAIBaseURL is the API root used when no base URL is configured.
+// Local servers (llama.cpp `llama-server`, Ollama, vLLM, LM Studio) expose the
+// same API under e.g. http://localhost:8080/v1 or http://localhost:11434/v1.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient is the TextModel backed by any server that speaks the OpenAI
+// chat completions API. It uses plain net/http so no extra SDK is required.
+type OpenAIClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+	TopP        float64       `json:"top_p"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// NewOpenAIClient creates a client for an OpenAI-compatible endpoint. The API
+// key may be empty for local servers that do not require authentication.
+func NewOpenAIClient(baseURL, apiKey, model string) (*OpenAIClient, error) {
+	if model == "" {
+		return nil, fmt.Errorf("model name is required for the OpenAI-compatible provider (set LLM_MODEL or -model)")
+	}
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+
+	return &OpenAIClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		// Timeouts are enforced per call via the context (DefaultAPITimeout)
+		client: &http.Client{},
+	}, nil
+}
+
+// Name implements TextModel.
+func (c *OpenAIClient) Name() string {
+	return fmt.Sprintf("OpenAI-compatible (%s)", c.model)
+}
+
+// GenerateText implements TextModel.
+func (c *OpenAIClient) GenerateText(ctx context.Context, prompt string) (string, error) {
+	payload, err := json.Marshal(chatRequest{
+		Model:       c.model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: 0.7,
+		TopP:        0.9,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result chatResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, truncateText(string(body), 300))
+		}
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != nil && result.Error.Message != "" {
+			return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, result.Error.Message)
+		}
+		return "", fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	if len(result.Choices) == 0 || strings.TrimSpace(result.Choices[0].Message.Content) == "" {
+		return "", ErrNoContent
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
+
+// Close implements TextModel. The HTTP client holds no resources.
+func (c *OpenAIClient) Close() error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// ProviderGemini selects the Google Gemini backend (default).
+	ProviderGemini = "gemini"
+	// ProviderOpenAI selects any OpenAI-compatible chat completions server.
+	ProviderOpenAI = "openai"
+)
+
+// ProviderConfig selects and configures the TextModel backend.
+//
+// Environment variables (read by ProviderConfigFromEnv):
+//
+//	LLM_PROVIDER      "gemini" (default) or "openai"
+//	LLM_MODEL         model name (default: gemini-2.5-flash for Gemini)
+//	GEMINI_API_KEY    required for the Gemini provider
+//	OPENAI_BASE_URL   e.g. http://localhost:11434/v1 for Ollama
+//	OPENAI_API_KEY    optional for local servers
+type ProviderConfig struct {
+	Provider string
+	Model    string
+	APIKey   string
+	BaseURL  string
+}
+
+// ProviderConfigFromEnv builds a ProviderConfig from environment variables.
+// A non-empty provider argument (e.g. from a command-line flag) overrides
+// LLM_PROVIDER.
+func ProviderConfigFromEnv(provider string) ProviderConfig {
+	if provider == "" {
+		provider = os.Getenv("LLM_PROVIDER")
+	}
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		provider = ProviderGemini
+	}
+
+	cfg := ProviderConfig{
+		Provider: provider,
+		Model:    os.Getenv("LLM_MODEL"),
+	}
+
+	switch provider {
+	case ProviderGemini:
+		cfg.APIKey = os.Getenv("GEMINI_API_KEY")
+	case ProviderOpenAI:
+		cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+		cfg.BaseURL = os.Getenv("OPENAI_BASE_URL")
+	}
+
+	return cfg
+}
+
+// NewTextModel creates the TextModel backend described by cfg.
+func NewTextModel(ctx context.Context, cfg ProviderConfig) (TextModel, error) {
+	switch cfg.Provider {
+	case ProviderGemini, "":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("GEMINI_API_KEY environment variable required for provider %q", ProviderGemini)
+		}
+		return NewGeminiClientWithModel(ctx, cfg.APIKey, cfg.Model)
+	case ProviderOpenAI:
+		return NewOpenAIClient(cfg.BaseURL, cfg.APIKey, cfg.Model)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (supported: %s, %s)", cfg.Provider, ProviderGemini, ProviderOpenAI)
+	}
+}
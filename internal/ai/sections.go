@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/releasenotes"
+)
+
+// RenderSecuritySection renders the "Security Fixes This Week" section from
+// published repository advisories and from the identifiers and security
+// notes extracted from stable releases. It is built without the model so
+// fixes are never dropped by summarization, and returns "" when there is
+// nothing to report. Identifiers already listed as advisories are not
+// repeated under the releases.
+func RenderSecuritySection(releases []models.Release, advisories []models.Advisory) string {
+	listed := make(map[string]bool)
+	for _, a := range advisories {
+		for _, id := range []string{a.GHSAID, a.CVEID} {
+			if id != "" {
+				listed[strings.ToUpper(id)] = true
+			}
+		}
+	}
+
+	var withFixes []models.Release
+	for _, r := range sortedByRepo(releases) {
+		if isPreRelease(r) {
+			continue
+		}
+		if len(r.SecurityFixes) == 0 {
+			if len(r.SecurityNotes) > 0 {
+				withFixes = append(withFixes, r)
+			}
+			continue
+		}
+		var fixes []models.SecurityFix
+		for _, f := range r.SecurityFixes {
+			if !listed[strings.ToUpper(f.ID)] {
+				fixes = append(fixes, f)
+			}
+		}
+		if len(fixes) > 0 {
+			r.SecurityFixes = fixes
+			withFixes = append(withFixes, r)
+		}
+	}
+	if len(withFixes) == 0 && len(advisories) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## 🔒 Security Fixes This Week\n\n")
+
+	if len(advisories) > 0 {
+		b.WriteString(renderAdvisories(advisories))
+		if len(withFixes) > 0 {
+			b.WriteString("\nReleases referencing security fixes:\n\n")
+		}
+	}
+
+	for _, r := range withFixes {
+		fmt.Fprintf(&b, "- **[%s %s](%s)**", r.RepoName, r.TagName, r.URL)
+		if len(r.SecurityFixes) > 0 {
+			links := make([]string, 0, len(r.SecurityFixes))
+			for _, f := range r.SecurityFixes {
+				links = append(links, fmt.Sprintf("[%s](%s)", f.ID, f.URL))
+			}
+			b.WriteString(" - " + strings.Join(links, ", "))
+		}
+		b.WriteString("\n")
+		// Notes add context only when there are no identifiers to link
+		if len(r.SecurityFixes) == 0 {
+			for _, n := range r.SecurityNotes[:min(2, len(r.SecurityNotes))] {
+				fmt.Fprintf(&b, "  - %s\n", sanitizeUTF8(n))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// severityRank orders advisories from critical to low.
+var severityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "moderate": 2, "low": 3}
+
+// renderAdvisories lists advisories from critical to low, each with its
+// severity, CVSS score and the affected and patched versions as published.
+func renderAdvisories(advisories []models.Advisory) string {
+	sorted := make([]models.Advisory, len(advisories))
+	copy(sorted, advisories)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, ok := severityRank[strings.ToLower(sorted[i].Severity)]
+		if !ok {
+			ri = len(severityRank)
+		}
+		rj, ok := severityRank[strings.ToLower(sorted[j].Severity)]
+		if !ok {
+			rj = len(severityRank)
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].CVSSScore > sorted[j].CVSSScore
+	})
+
+	var b strings.Builder
+	for _, a := range sorted {
+		fmt.Fprintf(&b, "- **[%s](%s)**", a.GHSAID, a.URL)
+		if a.CVEID != "" {
+			fmt.Fprintf(&b, " (%s)", a.CVEID)
+		}
+		fmt.Fprintf(&b, " %s/%s", a.RepoOwner, a.RepoName)
+
+		var rating []string
+		if a.Severity != "" {
+			rating = append(rating, strings.ToLower(a.Severity))
+		}
+		if a.CVSSScore > 0 {
+			rating = append(rating, fmt.Sprintf("CVSS %.1f", a.CVSSScore))
+		}
+		if len(rating) > 0 {
+			b.WriteString(" - " + strings.Join(rating, ", "))
+		}
+		fmt.Fprintf(&b, ": %s\n", sanitizeUTF8(strings.TrimSpace(a.Summary)))
+
+		for _, v := range a.Vulnerabilities {
+			pkg := strings.TrimSpace(v.Ecosystem + " " + v.Package)
+			if pkg == "" {
+				pkg = "affected package"
+			}
+			var parts []string
+			if v.VulnerableVersions != "" {
+				parts = append(parts, "affected "+v.VulnerableVersions)
+			}
+			if v.PatchedVersions != "" {
+				parts = append(parts, "patched "+v.PatchedVersions)
+			} else {
+				parts = append(parts, "no patched version published")
+			}
+			fmt.Fprintf(&b, "  - %s: %s\n", pkg, strings.Join(parts, ", "))
+		}
+	}
+	return b.String()
+}
+
+// RenderBreakingSection renders the "Breaking Changes & Deprecations" section
+// from the upgrade notes of stable releases, with links to the releases. Like
+// the security section it is built without the model and returns "" when no
+// release announces a breaking change or deprecation.
+func RenderBreakingSection(releases []models.Release) string {
+	var b strings.Builder
+	for _, r := range sortedByRepo(releases) {
+		if isPreRelease(r) {
+			continue
+		}
+		breaking, deprecations := releasenotes.UpgradeNotes(r)
+		if len(breaking) == 0 && len(deprecations) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "- **[%s %s](%s)**\n", r.RepoName, r.TagName, r.URL)
+		for _, item := range breaking[:min(3, len(breaking))] {
+			fmt.Fprintf(&b, "  - Breaking: %s\n", sanitizeUTF8(item))
+		}
+		for _, item := range deprecations[:min(2, len(deprecations))] {
+			fmt.Fprintf(&b, "  - Deprecated: %s\n", sanitizeUTF8(item))
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "## ⚠️ Breaking Changes & Deprecations\n\n" + b.String()
+}
+
+// sortedByRepo returns a copy of releases ordered by repository, then by
+// publication time.
+func sortedByRepo(releases []models.Release) []models.Release {
+	sorted := make([]models.Release, len(releases))
+	copy(sorted, releases)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.RepoOwner+"/"+a.RepoName != b.RepoOwner+"/"+b.RepoName {
+			return a.RepoOwner+"/"+a.RepoName < b.RepoOwner+"/"+b.RepoName
+		}
+		return a.PublishedAt.Before(b.PublishedAt)
+	})
+	return sorted
+}
+
+// RenderLandscapeSection renders the "CNCF Project Changes" section from the
+// changes the landscape sync found: projects that joined, moved up a
+// maturity level, were archived or left the landscape. It states the changes
+// as listed and returns "" when there are none.
+func RenderLandscapeSection(changes *models.LandscapeChanges) string {
+	if changes.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## 🌱 CNCF Project Changes\n\n")
+	for _, c := range changes.Promoted {
+		fmt.Fprintf(&b, "- %s moved from %s to %s\n", projectLink(c), c.From, c.To)
+	}
+	for _, c := range changes.Joined {
+		fmt.Fprintf(&b, "- %s joined the CNCF as %s project\n", projectLink(c), withArticle(c.To))
+	}
+	for _, c := range changes.Archived {
+		fmt.Fprintf(&b, "- %s was archived (previously %s)\n", projectLink(c), c.From)
+	}
+	for _, c := range changes.Removed {
+		fmt.Fprintf(&b, "- %s is no longer listed in the CNCF landscape (previously %s)\n", projectLink(c), c.From)
+	}
+	return b.String()
+}
+
+// projectLink links a project name to its GitHub repository when known.
+func projectLink(c models.ProjectChange) string {
+	if c.Owner == "" || c.Repo == "" {
+		return "**" + c.Name + "**"
+	}
+	return fmt.Sprintf("**[%s](https://github.com/%s/%s)**", c.Name, c.Owner, c.Repo)
+}
+
+// withArticle prefixes a maturity level with "a" or "an".
+func withArticle(level string) string {
+	if level != "" && strings.ContainsRune("aeiou", rune(level[0])) {
+		return "an " + level
+	}
+	return "a " + level
+}
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+func TestRenderSecuritySectionSkipsListedAdvisories(t *testing.T) {
+	advisories := []models.Advisory{
+		{GHSAID: "GHSA-aaaa-bbbb-cccc", CVEID: "CVE-2026-1111", RepoOwner: "envoyproxy", RepoName: "envoy", Severity: "high", URL: "https://github.com/advisories/GHSA-aaaa-bbbb-cccc"},
+	}
+	releases := []models.Release{
+		{RepoOwner: "envoyproxy", RepoName: "envoy", TagName: "v1.35.1", URL: "https://github.com/envoyproxy/envoy/releases/tag/v1.35.1", SecurityFixes: []models.SecurityFix{
+			{ID: "GHSA-aaaa-bbbb-cccc", URL: "https://github.com/advisories/GHSA-aaaa-bbbb-cccc"},
+			{ID: "CVE-2026-2222", URL: "https://nvd.nist.gov/vuln/detail/CVE-2026-2222"},
+		}},
+		{RepoOwner: "cilium", RepoName: "cilium", TagName: "v1.18.6", URL: "https://github.com/cilium/cilium/releases/tag/v1.18.6", SecurityFixes: []models.SecurityFix{
+			{ID: "cve-2026-1111", URL: "https://nvd.nist.gov/vuln/detail/CVE-2026-1111"},
+		}},
+	}
+
+	section := RenderSecuritySection(releases, advisories)
+
+	if n := strings.Count(section, "GHSA-aaaa-bbbb-cccc]"); n != 1 {
+		t.Errorf("advisory listed %d times, want 1:\n%s", n, section)
+	}
+	if !strings.Contains(section, "[CVE-2026-2222]") {
+		t.Errorf("unlisted fix missing:\n%s", section)
+	}
+	if strings.Contains(section, "cilium v1.18.6") {
+		t.Errorf("release whose fixes are all listed as advisories is repeated:\n%s", section)
+	}
+	if len(releases[0].SecurityFixes) != 2 {
+		t.Error("RenderSecuritySection modified its input")
+	}
+}
@@ -0,0 +1,270 @@
+package cncf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ShapeError reports landscape data that no longer has the structure the
+// sync relies on, listing every problem found so a layout change can be
+// fixed from the workflow log alone.
+type ShapeError struct {
+	Source   string
+	Problems []string
+}
+
+func (e *ShapeError) Error() string {
+	return fmt.Sprintf("landscape data from %s has an unexpected shape: %s", e.Source, strings.Join(e.Problems, "; "))
+}
+
+// Fetcher loads the CNCF projects from the landscape website or from a local
+// copy of its data.
+type Fetcher struct {
+	client            *http.Client
+	baseURL           string
+	inputPath         string
+	includeAdditional bool
+	archived          []models.Repository
+}
+
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		client:  &http.Client{Timeout: 60 * time.Second},
+		baseURL: LandscapeURL,
+	}
+}
+
+// UseHTTPClient sets the client used for landscape requests.
+func (f *Fetcher) UseHTTPClient(client *http.Client) {
+	f.client = client
+}
+
+// UseBaseURL points the fetcher at another landscape instance or a mirror.
+func (f *Fetcher) UseBaseURL(baseURL string) {
+	f.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// UseFile reads the landscape data from a local file instead of the
+// network: a saved landscape page (window.baseDS), full.json or a
+// landscape.yml.
+func (f *Fetcher) UseFile(path string) {
+	f.inputPath = path
+}
+
+// IncludeAdditionalRepos also tracks the extra repositories of a project.
+func (f *Fetcher) IncludeAdditionalRepos(enabled bool) {
+	f.includeAdditional = enabled
+}
+
+// FetchCNCFProjects fetches the list of all CNCF projects from the Landscape.
+// Repositories come from the landscape's own item data; knownRepos is only
+// used for projects without a GitHub repository in that data.
+func FetchCNCFProjects(includeAdditional bool) ([]models.Repository, error) {
+	f := NewFetcher()
+	f.IncludeAdditionalRepos(includeAdditional)
+	return f.Fetch(context.Background())
+}
+
+// Fetch loads the landscape items and maps the CNCF projects among them to
+// repositories. Over the network, full.json is tried first and the data
+// embedded in the landscape page second.
+func (f *Fetcher) Fetch(ctx context.Context) ([]models.Repository, error) {
+	var items []landscapeItem
+	var err error
+
+	if f.inputPath != "" {
+		log.Printf("Reading CNCF projects from %s...", f.inputPath)
+		items, err = f.readFile()
+	} else {
+		log.Printf("Fetching CNCF projects from %s...", f.baseURL)
+		items, err = f.fetchRemote(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Found %d items in CNCF Landscape", len(items))
+	f.archived = archivedFromItems(items)
+	return projectsFromItems(items, f.includeAdditional), nil
+}
+
+// Archived returns the archived CNCF projects seen by the last Fetch.
+func (f *Fetcher) Archived() []models.Repository {
+	return f.archived
+}
+
+func (f *Fetcher) fetchRemote(ctx context.Context) ([]landscapeItem, error) {
+	fullURL := f.baseURL + fullDataPath
+	items, fullErr := f.fetchItems(ctx, fullURL)
+	if fullErr == nil {
+		return items, nil
+	}
+	log.Printf("Full landscape data unavailable (%v), falling back to the landscape page", fullErr)
+
+	items, pageErr := f.fetchItems(ctx, f.baseURL)
+	if pageErr != nil {
+		return nil, fmt.Errorf("both landscape sources failed: %s: %v; %s: %w", fullURL, fullErr, f.baseURL, pageErr)
+	}
+	return items, nil
+}
+
+func (f *Fetcher) fetchItems(ctx context.Context, url string) ([]landscapeItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return parseLandscapeData(body, url)
+}
+
+func (f *Fetcher) readFile() ([]landscapeItem, error) {
+	data, err := os.ReadFile(f.inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseLandscapeData(data, f.inputPath)
+}
+
+// parseLandscapeData detects the format of data (JSON items, landscape page
+// HTML or landscape.yml), decodes the items and checks their shape.
+func parseLandscapeData(data []byte, source string) ([]landscapeItem, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	var items []landscapeItem
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var parsed baseDS
+		if err := json.Unmarshal(trimmed, &parsed); err != nil {
+			return nil, &ShapeError{Source: source, Problems: []string{fmt.Sprintf("not a JSON object with an items list: %v", err)}}
+		}
+		items = parsed.Items
+	case baseDSMarkerRe.Match(data):
+		parsed, err := extractBaseDS(string(data))
+		if err != nil {
+			if shapeErr, ok := err.(*ShapeError); ok {
+				shapeErr.Source = source
+			}
+			return nil, err
+		}
+		items = parsed.Items
+	case bytes.Contains(data, []byte("landscape:")):
+		var err error
+		items, err = parseLandscapeYAML(data, source)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &ShapeError{Source: source, Problems: []string{"neither landscape JSON, a landscape page with window.baseDS, nor a landscape.yml"}}
+	}
+
+	if err := validateItems(items, source); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// landscapeYAML is the layout of the cncf/landscape landscape.yml file.
+type landscapeYAML struct {
+	Landscape []struct {
+		Name          string `yaml:"name"`
+		Subcategories []struct {
+			Name  string `yaml:"name"`
+			Items []struct {
+				Name            string `yaml:"name"`
+				RepoURL         string `yaml:"repo_url"`
+				Project         string `yaml:"project"`
+				AdditionalRepos []struct {
+					RepoURL string `yaml:"repo_url"`
+				} `yaml:"additional_repos"`
+			} `yaml:"items"`
+		} `yaml:"subcategories"`
+	} `yaml:"landscape"`
+}
+
+func parseLandscapeYAML(data []byte, source string) ([]landscapeItem, error) {
+	var doc landscapeYAML
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &ShapeError{Source: source, Problems: []string{fmt.Sprintf("invalid landscape.yml: %v", err)}}
+	}
+
+	var items []landscapeItem
+	for _, cat := range doc.Landscape {
+		for _, sub := range cat.Subcategories {
+			for _, it := range sub.Items {
+				item := landscapeItem{
+					Name:        it.Name,
+					Maturity:    it.Project,
+					Category:    cat.Name,
+					Subcategory: sub.Name,
+					RepoURL:     it.RepoURL,
+				}
+				for _, r := range it.AdditionalRepos {
+					item.AdditionalRepos = append(item.AdditionalRepos, additionalRepo{RepoURL: r.RepoURL})
+				}
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+// validateItems checks the fields the sync depends on. A handful of odd
+// items is tolerated; a structural change is not.
+func validateItems(items []landscapeItem, source string) error {
+	if len(items) == 0 {
+		return &ShapeError{Source: source, Problems: []string{"no items found (expected a non-empty \"items\" list)"}}
+	}
+
+	var unnamed, projects, uncategorized int
+	for _, item := range items {
+		if item.Name == "" {
+			unnamed++
+		}
+		if item.Maturity != "" {
+			projects++
+		}
+		if item.Category == "" && item.Subcategory == "" {
+			uncategorized++
+		}
+	}
+
+	var problems []string
+	if unnamed*10 > len(items) {
+		problems = append(problems, fmt.Sprintf("%d of %d items have no \"name\"", unnamed, len(items)))
+	}
+	if projects == 0 {
+		problems = append(problems, "no item has a \"maturity\" (graduated/incubating/sandbox), so no CNCF project can be identified")
+	}
+	if uncategorized*2 > len(items) {
+		problems = append(problems, fmt.Sprintf("%d of %d items have neither \"category\" nor \"subcategory\"", uncategorized, len(items)))
+	}
+	if len(problems) > 0 {
+		return &ShapeError{Source: source, Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package cncf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// countingTransport counts the requests made through a client.
+type countingTransport struct {
+	requests []string
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req.URL.Path)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newLandscapeServer serves the given fixtures by path; other paths are 404.
+func newLandscapeServer(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(readFixture(t, name))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestFetcher(srv *httptest.Server) (*Fetcher, *countingTransport) {
+	transport := &countingTransport{}
+	f := NewFetcher()
+	f.UseHTTPClient(&http.Client{Transport: transport})
+	f.UseBaseURL(srv.URL + "/")
+	return f, transport
+}
+
+func repoKeys(repos []models.Repository) map[string]models.Repository {
+	keys := make(map[string]models.Repository, len(repos))
+	for _, r := range repos {
+		keys[r.Owner+"/"+r.Repo] = r
+	}
+	return keys
+}
+
+func TestFetchFullJSON(t *testing.T) {
+	srv := newLandscapeServer(t, map[string]string{"/data/full.json": "full.json"})
+	f, transport := newTestFetcher(srv)
+
+	repos, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(transport.requests) != 1 || transport.requests[0] != "/data/full.json" {
+		t.Errorf("requests = %q, want only /data/full.json through the injected client", transport.requests)
+	}
+
+	got := repoKeys(repos)
+	want := map[string]models.Repository{
+		"kubernetes/kubernetes": {Name: "Kubernetes", Category: "orchestration", CNCFStatus: "graduated"},
+		"argoproj/argo-cd":      {Name: "Argo", Category: "ci-cd", CNCFStatus: "graduated"},
+		"kedacore/keda":         {Name: "KEDA", Category: "orchestration", CNCFStatus: "graduated"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got repositories %v, want %d", got, len(want))
+	}
+	for key, w := range want {
+		r, ok := got[key]
+		if !ok {
+			t.Errorf("missing %s", key)
+			continue
+		}
+		if r.Name != w.Name || r.Category != w.Category || r.CNCFStatus != w.CNCFStatus {
+			t.Errorf("%s = %+v, want %+v", key, r, w)
+		}
+	}
+
+	archived := f.Archived()
+	if len(archived) != 1 || archived[0].Name != "Brigade" || archived[0].Owner != "brigadecore" {
+		t.Errorf("Archived() = %+v, want Brigade", archived)
+	}
+}
+
+func TestFetchFallsBackToLandscapePage(t *testing.T) {
+	srv := newLandscapeServer(t, map[string]string{"/": "landscape.html"})
+	f, transport := newTestFetcher(srv)
+
+	repos, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(transport.requests) != 2 {
+		t.Errorf("requests = %q, want full.json then the page", transport.requests)
+	}
+
+	got := repoKeys(repos)
+	if _, ok := got["prometheus/prometheus"]; !ok {
+		t.Errorf("missing prometheus/prometheus in %v", got)
+	}
+	if r, ok := got["cilium/cilium"]; !ok || r.Category != "networking" {
+		t.Errorf("cilium/cilium = %+v, want category networking", r)
+	}
+}
+
+func TestFetchChangedShape(t *testing.T) {
+	srv := newLandscapeServer(t, map[string]string{"/": "landscape-changed-shape.html"})
+	f, _ := newTestFetcher(srv)
+
+	_, err := f.Fetch(context.Background())
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("err = %v, want a *ShapeError", err)
+	}
+	if shapeErr.Source != srv.URL {
+		t.Errorf("Source = %q, want %q", shapeErr.Source, srv.URL)
+	}
+	if len(shapeErr.Problems) == 0 {
+		t.Error("ShapeError lists no problems")
+	}
+}
+
+func TestFetchFileWithAdditionalRepos(t *testing.T) {
+	f := NewFetcher()
+	f.UseHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil, errors.New("offline")
+	})})
+	f.UseFile("testdata/landscape.yml")
+	f.IncludeAdditionalRepos(true)
+
+	repos, err := f.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	got := repoKeys(repos)
+	for key, name := range map[string]string{
+		"containerd/containerd": "containerd",
+		"containerd/nerdctl":    "containerd (nerdctl)",
+		"falcosecurity/falco":   "Falco",
+	} {
+		if r, ok := got[key]; !ok || r.Name != name {
+			t.Errorf("%s = %+v, want name %q", key, r, name)
+		}
+	}
+	if _, ok := got["vendor/runtime"]; ok {
+		t.Error("item without maturity tracked as a CNCF project")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
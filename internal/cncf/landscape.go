@@ -3,9 +3,7 @@ package cncf
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
 
@@ -15,23 +13,79 @@ import (
 const (
 	// LandscapeURL is the CNCF landscape page that embeds all project data
 	LandscapeURL = "https://landscape.cncf.io"
+	// fullDataPath is the landscape's complete item data, including
+	// repositories, relative to the landscape URL
+	fullDataPath = "/data/full.json"
 )
 
-// landscapeItem represents a single item from the embedded landscape data
+// landscapeItem represents a single item from the landscape data. The
+// repositories come either as a list with a primary flag (full.json) or as
+// repo_url plus additional_repos (landscape.yml style).
 type landscapeItem struct {
-	Name        string `json:"name"`
-	Maturity    string `json:"maturity,omitempty"`
-	Category    string `json:"category"`
-	Subcategory string `json:"subcategory"`
-	OSS         bool   `json:"oss,omitempty"`
+	Name            string           `json:"name"`
+	Maturity        string           `json:"maturity,omitempty"`
+	Category        string           `json:"category"`
+	Subcategory     string           `json:"subcategory"`
+	OSS             bool             `json:"oss,omitempty"`
+	Repositories    []landscapeRepo  `json:"repositories,omitempty"`
+	RepoURL         string           `json:"repo_url,omitempty"`
+	AdditionalRepos []additionalRepo `json:"additional_repos,omitempty"`
 }
 
-// baseDS represents the window.baseDS structure embedded in the landscape HTML
+type landscapeRepo struct {
+	URL     string `json:"url"`
+	Primary *bool  `json:"primary,omitempty"`
+}
+
+type additionalRepo struct {
+	RepoURL string `json:"repo_url"`
+}
+
+// repoURLs returns the item's primary repository URL and any others.
+func (item landscapeItem) repoURLs() (string, []string) {
+	primary := item.RepoURL
+	var extra []string
+	for _, r := range item.Repositories {
+		if primary == "" && r.Primary != nil && *r.Primary {
+			primary = r.URL
+			continue
+		}
+		if r.URL != primary {
+			extra = append(extra, r.URL)
+		}
+	}
+	// Without a primary flag the first repository is the main one
+	if primary == "" && len(extra) > 0 {
+		primary, extra = extra[0], extra[1:]
+	}
+	for _, r := range item.AdditionalRepos {
+		extra = append(extra, r.RepoURL)
+	}
+	return primary, extra
+}
+
+// githubRepoRe extracts owner and repo from a GitHub repository URL.
+var githubRepoRe = regexp.MustCompile(`^https?://(?:www\.)?github\.com/([\w.-]+)/([\w.-]+?)(?:\.git)?/?(?:[/?#].*)?$`)
+
+func parseGitHubURL(url string) (owner, repo string, ok bool) {
+	m := githubRepoRe.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// baseDSMarkerRe finds the start of the window.baseDS object in the page.
+var baseDSMarkerRe = regexp.MustCompile(`window\.baseDS\s*=\s*`)
+
+// baseDS represents the window.baseDS structure embedded in the landscape
+// HTML; full.json has the same top-level items list
 type baseDS struct {
 	Items []landscapeItem `json:"items"`
 }
 
-// knownRepos maps CNCF project names to their primary GitHub owner/repo.
+// knownRepos maps CNCF project names to their primary GitHub owner/repo. It
+// is only a fallback for items whose landscape data has no GitHub repository.
 var knownRepos = map[string]struct{ Owner, Repo string }{
 	// Graduated
 	"Kubernetes":                 {"kubernetes", "kubernetes"},
@@ -126,89 +180,111 @@ var knownRepos = map[string]struct{ Owner, Repo string }{
 	"Cloud Native Buildpacks": {"buildpacks", "pack"},
 }
 
-// FetchCNCFProjects fetches the list of all CNCF projects from the Landscape page
-// by parsing the embedded window.baseDS JSON data.
-func FetchCNCFProjects() ([]models.Repository, error) {
-	log.Println("Fetching CNCF projects from Landscape...")
-
-	resp, err := http.Get(LandscapeURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch CNCF landscape: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("CNCF landscape returned status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Extract window.baseDS JSON from the HTML
-	data, err := extractBaseDS(string(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract landscape data: %w", err)
-	}
-
-	log.Printf("Found %d items in CNCF Landscape", len(data.Items))
-
+// projectsFromItems maps CNCF projects (items with a maturity) to the
+// repositories to track.
+func projectsFromItems(items []landscapeItem, includeAdditional bool) []models.Repository {
 	var repos []models.Repository
 	seen := make(map[string]bool)
 	var unmapped []string
+	fromKnown := 0
+
+	add := func(owner, repo, name string, item landscapeItem) {
+		key := strings.ToLower(owner + "/" + repo)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		repos = append(repos, models.Repository{
+			Owner:      owner,
+			Repo:       repo,
+			Name:       name,
+			Category:   normalizeCategory(item.Subcategory, item.Category),
+			CNCFStatus: item.Maturity,
+		})
+	}
 
-	for _, item := range data.Items {
+	for _, item := range items {
 		// Only include CNCF projects (items with maturity field)
 		if item.Maturity == "" || item.Maturity == "archived" {
 			continue
 		}
 
-		repoInfo, ok := knownRepos[item.Name]
+		primary, extra := item.repoURLs()
+		owner, repo, ok := parseGitHubURL(primary)
 		if !ok {
-			unmapped = append(unmapped, fmt.Sprintf("%s (%s)", item.Name, item.Maturity))
-			continue
+			known, found := knownRepos[item.Name]
+			if !found {
+				unmapped = append(unmapped, fmt.Sprintf("%s (%s)", item.Name, item.Maturity))
+				continue
+			}
+			owner, repo = known.Owner, known.Repo
+			fromKnown++
 		}
+		add(owner, repo, item.Name, item)
 
-		key := strings.ToLower(repoInfo.Owner + "/" + repoInfo.Repo)
-		if seen[key] {
+		if !includeAdditional {
 			continue
 		}
-		seen[key] = true
-
-		category := normalizeCategory(item.Subcategory, item.Category)
-
-		repos = append(repos, models.Repository{
-			Owner:      repoInfo.Owner,
-			Repo:       repoInfo.Repo,
-			Name:       item.Name,
-			Category:   category,
-			CNCFStatus: item.Maturity,
-		})
+		for _, url := range extra {
+			if o, r, ok := parseGitHubURL(url); ok {
+				add(o, r, fmt.Sprintf("%s (%s)", item.Name, r), item)
+			}
+		}
 	}
 
+	if fromKnown > 0 {
+		log.Printf("Used the built-in knownRepos mapping for %d projects without a GitHub repository in the landscape data", fromKnown)
+	}
 	if len(unmapped) > 0 {
-		log.Printf("Note: %d CNCF projects not in known repos mapping (add to landscape.go knownRepos if needed):", len(unmapped))
+		log.Printf("Note: %d CNCF projects have no GitHub repository (add an override in additional-repos.yaml if needed):", len(unmapped))
 		for _, name := range unmapped {
 			log.Printf("  - %s", name)
 		}
 	}
 
 	log.Printf("Extracted %d GitHub repositories from CNCF projects", len(repos))
-	return repos, nil
+	return repos
+}
+
+// archivedFromItems lists the archived CNCF projects. They are not tracked,
+// but the sync needs them to tell an archival apart from a removal. Owner
+// and Repo are empty when the landscape has no GitHub repository for one.
+func archivedFromItems(items []landscapeItem) []models.Repository {
+	var archived []models.Repository
+	for _, item := range items {
+		if item.Maturity != "archived" {
+			continue
+		}
+		primary, _ := item.repoURLs()
+		owner, repo, ok := parseGitHubURL(primary)
+		if !ok {
+			if known, found := knownRepos[item.Name]; found {
+				owner, repo = known.Owner, known.Repo
+			}
+		}
+		archived = append(archived, models.Repository{
+			Owner:      owner,
+			Repo:       repo,
+			Name:       item.Name,
+			Category:   normalizeCategory(item.Subcategory, item.Category),
+			CNCFStatus: item.Maturity,
+		})
+	}
+	return archived
 }
 
-// extractBaseDS parses the window.baseDS JSON from the landscape HTML page
+// extractBaseDS parses the window.baseDS JSON from the landscape HTML page.
+// The object is decoded from its opening brace, so whatever follows it in
+// the script does not matter.
 func extractBaseDS(html string) (*baseDS, error) {
-	re := regexp.MustCompile(`window\.baseDS\s*=\s*(\{.+?\});\s*\n`)
-	matches := re.FindStringSubmatch(html)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not find window.baseDS in landscape HTML")
+	loc := baseDSMarkerRe.FindStringIndex(html)
+	if loc == nil {
+		return nil, &ShapeError{Source: "landscape page", Problems: []string{"window.baseDS assignment not found in the HTML"}}
 	}
 
 	var data baseDS
-	if err := json.Unmarshal([]byte(matches[1]), &data); err != nil {
-		return nil, fmt.Errorf("failed to parse baseDS JSON: %w", err)
+	if err := json.NewDecoder(strings.NewReader(html[loc[1]:])).Decode(&data); err != nil {
+		return nil, &ShapeError{Source: "landscape page", Problems: []string{fmt.Sprintf("window.baseDS is not a JSON object: %v", err)}}
 	}
 
 	return &data, nil
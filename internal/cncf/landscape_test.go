@@ -0,0 +1,124 @@
+package cncf
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestExtractBaseDS(t *testing.T) {
+	data, err := extractBaseDS(string(readFixture(t, "landscape.html")))
+	if err != nil {
+		t.Fatalf("extractBaseDS: %v", err)
+	}
+
+	want := []landscapeItem{
+		{Name: "Prometheus", Category: "Observability and Analysis", Subcategory: "Monitoring", Maturity: "graduated", OSS: true},
+		{Name: "Cilium", Category: "Runtime", Subcategory: "Cloud Native Network", Maturity: "graduated", OSS: true},
+		{Name: "Some Vendor Product", Category: "Observability and Analysis", Subcategory: "Observability"},
+	}
+	if len(data.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(data.Items), len(want))
+	}
+	for i, item := range data.Items {
+		w := want[i]
+		if item.Name != w.Name || item.Category != w.Category || item.Subcategory != w.Subcategory || item.Maturity != w.Maturity || item.OSS != w.OSS {
+			t.Errorf("item %d = %+v, want %+v", i, item, w)
+		}
+	}
+}
+
+func TestExtractBaseDSWithoutAssignment(t *testing.T) {
+	_, err := extractBaseDS("<html><body><script>window.other = {};</script></body></html>")
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("err = %v, want a *ShapeError", err)
+	}
+	if len(shapeErr.Problems) != 1 || !strings.Contains(shapeErr.Problems[0], "window.baseDS") {
+		t.Errorf("Problems = %q", shapeErr.Problems)
+	}
+}
+
+func TestParseLandscapeDataChangedShape(t *testing.T) {
+	_, err := parseLandscapeData(readFixture(t, "landscape-changed-shape.html"), "landscape-changed-shape.html")
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("err = %v, want a *ShapeError", err)
+	}
+	if shapeErr.Source != "landscape-changed-shape.html" {
+		t.Errorf("Source = %q", shapeErr.Source)
+	}
+	if len(shapeErr.Problems) == 0 || !strings.Contains(shapeErr.Problems[0], "no items found") {
+		t.Errorf("Problems = %q, want the missing items list", shapeErr.Problems)
+	}
+	if !strings.Contains(err.Error(), "unexpected shape") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestValidateItemsListsEveryProblem(t *testing.T) {
+	items := []landscapeItem{{Name: "a"}, {}, {Name: "c"}}
+	err := validateItems(items, "test")
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("err = %v, want a *ShapeError", err)
+	}
+	if len(shapeErr.Problems) != 3 {
+		t.Errorf("Problems = %q, want unnamed, maturity and category problems", shapeErr.Problems)
+	}
+}
+
+func TestParseLandscapeDataFormats(t *testing.T) {
+	tests := []struct {
+		fixture string
+		items   int
+	}{
+		{"full.json", 6},
+		{"landscape.html", 3},
+		{"landscape.yml", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			items, err := parseLandscapeData(readFixture(t, tt.fixture), tt.fixture)
+			if err != nil {
+				t.Fatalf("parseLandscapeData: %v", err)
+			}
+			if len(items) != tt.items {
+				t.Errorf("got %d items, want %d", len(items), tt.items)
+			}
+		})
+	}
+}
+
+func TestNormalizeCategory(t *testing.T) {
+	tests := []struct {
+		subcategory string
+		category    string
+		want        string
+	}{
+		{"Container Runtime", "Runtime", "container-runtime"},
+		{"Scheduling & Orchestration", "Orchestration & Management", "orchestration"},
+		{"Continuous Integration & Delivery", "App Definition and Development", "ci-cd"},
+		{"Security & Compliance", "Provisioning", "security"},
+		{"Cloud Native Network", "Runtime", "networking"},
+		{"Service Proxy", "Orchestration & Management", "networking"},
+		{"Some New Subcategory", "Runtime", "some-new-subcategory"},
+		{"", "Observability and Analysis", "observability-and-analysis"},
+		{"", "", "other"},
+	}
+	for _, tt := range tests {
+		if got := normalizeCategory(tt.subcategory, tt.category); got != tt.want {
+			t.Errorf("normalizeCategory(%q, %q) = %q, want %q", tt.subcategory, tt.category, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// maxAdvisoryPages caps advisory pagination per repository.
+const maxAdvisoryPages = 5
+
+// GetAdvisoriesInRange returns the repository security advisories published
+// within [start, end). Withdrawn advisories are skipped.
+func (c *Client) GetAdvisoriesInRange(ctx context.Context, owner, repo, category string, start, end time.Time) ([]models.Advisory, error) {
+	var advisories []models.Advisory
+
+	opts := &github.ListRepositorySecurityAdvisoriesOptions{
+		State:             "published",
+		Sort:              "published",
+		Direction:         "desc",
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+	for page := 1; page <= maxAdvisoryPages; page++ {
+		var ghAdvisories []*github.SecurityAdvisory
+		var resp *github.Response
+		err := c.call(ctx, func() (*github.Response, error) {
+			var err error
+			ghAdvisories, resp, err = c.gh.SecurityAdvisories.ListRepositorySecurityAdvisories(ctx, owner, repo, opts)
+			return resp, err
+		})
+		if err != nil {
+			// Repos without advisories enabled answer 404
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		reachedStart := false
+		for _, a := range ghAdvisories {
+			if a.PublishedAt == nil || a.WithdrawnAt != nil {
+				continue
+			}
+			published := a.PublishedAt.Time
+			if published.Before(start) {
+				reachedStart = true
+				continue
+			}
+			if !published.Before(end) {
+				continue
+			}
+			advisories = append(advisories, toAdvisory(a, owner, repo, category))
+		}
+
+		if reachedStart || resp == nil || resp.After == "" {
+			break
+		}
+		opts.After = resp.After
+	}
+
+	return advisories, nil
+}
+
+func toAdvisory(a *github.SecurityAdvisory, owner, repo, category string) models.Advisory {
+	adv := models.Advisory{
+		GHSAID:      a.GetGHSAID(),
+		CVEID:       a.GetCVEID(),
+		RepoOwner:   owner,
+		RepoName:    repo,
+		Category:    category,
+		Summary:     a.GetSummary(),
+		Severity:    a.GetSeverity(),
+		CWEs:        a.CWEIDs,
+		URL:         a.GetHTMLURL(),
+		PublishedAt: a.GetPublishedAt().Time,
+	}
+	if a.CVSS != nil {
+		if score := a.CVSS.GetScore(); score != nil {
+			adv.CVSSScore = *score
+		}
+		adv.CVSSVector = a.CVSS.GetVectorString()
+	}
+	for _, v := range a.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		vuln := models.AdvisoryVulnerability{
+			VulnerableVersions: v.GetVulnerableVersionRange(),
+			PatchedVersions:    v.GetPatchedVersions(),
+		}
+		if v.Package != nil {
+			vuln.Ecosystem = v.Package.GetEcosystem()
+			vuln.Package = v.Package.GetName()
+		}
+		if vuln.PatchedVersions == "" && v.FirstPatchedVersion != nil {
+			vuln.PatchedVersions = v.FirstPatchedVersion.GetIdentifier()
+		}
+		adv.Vulnerabilities = append(adv.Vulnerabilities, vuln)
+	}
+	return adv
+}
+
+// FetchAllAdvisories collects the advisories published in [start, end) for
+// all repos on the worker pool. Repos that failed are returned as skipped.
+func (c *Client) FetchAllAdvisories(ctx context.Context, repos []models.Repository, start, end time.Time) ([]models.Advisory, []SkippedRepo, error) {
+	log.Printf("Fetching security advisories from %s to %s for %d repos...",
+		start.Format("2006-01-02"), end.Format("2006-01-02"), len(repos))
+
+	type advisoryResult struct {
+		advisories []models.Advisory
+		err        error
+	}
+	results := make([]advisoryResult, len(repos))
+	err := c.runPool(ctx, len(repos), func(i int) {
+		repo := repos[i]
+		advisories, err := c.GetAdvisoriesInRange(ctx, repo.Owner, repo.Repo, repo.Category, start, end)
+		results[i] = advisoryResult{advisories: advisories, err: err}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var all []models.Advisory
+	var skipped []SkippedRepo
+	for i, repo := range repos {
+		name := repo.Owner + "/" + repo.Repo
+		if results[i].err != nil {
+			log.Printf("  Error fetching advisories for %s: %v", name, results[i].err)
+			skipped = append(skipped, SkippedRepo{Repo: name, Err: results[i].err})
+			continue
+		}
+		for _, a := range results[i].advisories {
+			log.Printf("  %s: %s (%s)", name, a.GHSAID, a.Severity)
+		}
+		all = append(all, results[i].advisories...)
+	}
+
+	return all, skipped, nil
+}
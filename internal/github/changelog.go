@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/mfahlandt/lwcn/internal/releasenotes"
+)
+
+// maxExpandedBody caps the changelog section stored on a release.
+const maxExpandedBody = 20000
+
+// maxLinkOnlyBody is the longest body still treated as a pointer to a
+// changelog file rather than release notes in their own right.
+const maxLinkOnlyBody = 2000
+
+// changelogLinkRe matches a link to a Markdown changelog file in a GitHub
+// repository, e.g. .../blob/master/CHANGELOG/CHANGELOG-1.30.md#v1304.
+var changelogLinkRe = regexp.MustCompile(`https://github\.com/([\w.-]+)/([\w.-]+)/blob/([^/\s)]+)/([^\s)#]*(?i:changelog|release-notes|releases)[^\s)#]*\.md)(?:#([\w.-]+))?`)
+
+var mdHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// changelogRef identifies a changelog file at a ref.
+type changelogRef struct {
+	owner, repo, ref, path string
+}
+
+// expandChangelogs replaces link-only release bodies with the linked
+// changelog section. Files are downloaded once per run; failures only leave
+// the release as it was.
+func (c *Client) expandChangelogs(ctx context.Context, results []repoResult) {
+	files := make(map[changelogRef]string)
+
+	for i := range results {
+		for j := range results[i].releases {
+			r := &results[i].releases[j]
+			ref, anchor, ok := linkedChangelog(r.Body)
+			if !ok {
+				continue
+			}
+
+			content, cached := files[ref]
+			if !cached {
+				var err error
+				content, err = c.downloadFile(ctx, ref)
+				if err != nil {
+					log.Printf("  %s/%s %s: failed to fetch %s: %v", r.RepoOwner, r.RepoName, r.TagName, ref.path, err)
+				}
+				files[ref] = content
+			}
+			if content == "" {
+				continue
+			}
+
+			section := changelogSection(content, anchor, r.TagName)
+			if section == "" {
+				log.Printf("  %s/%s %s: no section for the release in %s", r.RepoOwner, r.RepoName, r.TagName, ref.path)
+				continue
+			}
+			if len(section) > maxExpandedBody {
+				section = section[:maxExpandedBody]
+			}
+			r.ExpandedBody = section
+			r.NotesURL = fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", ref.owner, ref.repo, ref.ref, ref.path)
+			if anchor != "" {
+				r.NotesURL += "#" + anchor
+			}
+			log.Printf("  %s/%s %s: expanded release notes from %s (%d bytes)", r.RepoOwner, r.RepoName, r.TagName, ref.path, len(section))
+		}
+	}
+}
+
+// linkedChangelog reports the changelog file a body points to when the body
+// is only a pointer: short and without list entries of its own.
+func linkedChangelog(body string) (changelogRef, string, bool) {
+	if len(body) > maxLinkOnlyBody || releasenotes.Parse(body) != nil {
+		return changelogRef{}, "", false
+	}
+	m := changelogLinkRe.FindStringSubmatch(body)
+	if m == nil {
+		return changelogRef{}, "", false
+	}
+	return changelogRef{owner: m[1], repo: m[2], ref: m[3], path: m[4]}, m[5], true
+}
+
+func (c *Client) downloadFile(ctx context.Context, ref changelogRef) (string, error) {
+	var rc io.ReadCloser
+	err := c.call(ctx, func() (*github.Response, error) {
+		var resp *github.Response
+		var err error
+		rc, resp, err = c.gh.Repositories.DownloadContents(ctx, ref.owner, ref.repo, ref.path,
+			&github.RepositoryContentGetOptions{Ref: ref.ref})
+		return resp, err
+	})
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// changelogSection returns the section of a Markdown changelog headed by the
+// anchor (GitHub heading slug) or, failing that, by the release tag. The
+// section ends at the next heading of the same or a higher level.
+func changelogSection(content, anchor, tag string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	mentionsTag := tagHeadingRe(tag)
+	start, level := -1, 0
+	for i, line := range lines {
+		m := mdHeadingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if (anchor != "" && headingSlug(m[2]) == strings.ToLower(anchor)) || (mentionsTag != nil && mentionsTag.MatchString(m[2])) {
+			start, level = i, len(m[1])
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if m := mdHeadingRe.FindStringSubmatch(lines[i]); m != nil && len(m[1]) <= level {
+			end = i
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+}
+
+// headingSlug mirrors GitHub's heading anchors: lowercase, punctuation
+// dropped, spaces turned into hyphens.
+func headingSlug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// tagHeadingRe matches headings that name the tag as a whole word, with or
+// without its "v" prefix ("v1.30.4", "[1.30.4] - 2026-04-01").
+func tagHeadingRe(tag string) *regexp.Regexp {
+	version := strings.TrimPrefix(tag, "v")
+	if version == "" {
+		return nil
+	}
+	return regexp.MustCompile(`(^|[^\w.])v?` + regexp.QuoteMeta(version) + `($|[^\w.])`)
+}
@@ -2,16 +2,32 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v60/github"
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/releasenotes"
+	"github.com/mfahlandt/lwcn/internal/state"
+	"github.com/mfahlandt/lwcn/internal/version"
 	"golang.org/x/oauth2"
 )
 
+// DefaultWorkers is the number of repositories fetched concurrently.
+const DefaultWorkers = 8
+
+// maxReleasePages caps pagination per repository (100 releases per page).
+const maxReleasePages = 30
+
 type Client struct {
-	gh *github.Client
+	gh         *github.Client
+	httpClient *http.Client
+	gate       *rateGate
+	workers    int
+	useGraphQL bool
 }
 
 func NewClient(token string) *Client {
@@ -22,99 +38,334 @@ func NewClient(token string) *Client {
 	tc := oauth2.NewClient(ctx, ts)
 
 	return &Client{
-		gh: github.NewClient(tc),
+		gh:         github.NewClient(tc),
+		httpClient: tc,
+		gate:       &rateGate{},
+		workers:    DefaultWorkers,
+	}
+}
+
+// UseGraphQL switches release and stats fetching to batched GraphQL queries
+// (many repositories per request) instead of per-repository REST calls.
+func (c *Client) UseGraphQL(enabled bool) {
+	c.useGraphQL = enabled
+}
+
+// SetWorkers sets how many repositories are fetched concurrently.
+func (c *Client) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
 	}
+	c.workers = n
+}
+
+// SkippedRepo is a repository whose releases could not be fetched.
+type SkippedRepo struct {
+	Repo string
+	Err  error
+}
+
+func (s SkippedRepo) String() string {
+	return fmt.Sprintf("%s: %v", s.Repo, s.Err)
 }
 
 func (c *Client) GetReleasesLastWeek(ctx context.Context, owner, repo, category string) ([]models.Release, error) {
 	oneWeekAgo := time.Now().AddDate(0, 0, -7)
-	return c.GetReleasesInRange(ctx, owner, repo, category, oneWeekAgo, time.Now())
+	return c.GetReleasesInRange(ctx, models.Repository{Owner: owner, Repo: repo, Category: category}, oneWeekAgo, time.Now())
 }
 
-// GetReleasesInRange fetches releases published within the given time range
-func (c *Client) GetReleasesInRange(ctx context.Context, owner, repo, category string, start, end time.Time) ([]models.Release, error) {
+// GetReleasesInRange fetches releases published within [start, end).
+// GitHub lists releases newest first, so pages are walked until a page
+// contains no release published (or created) at or after start, which keeps
+// backfills of old weeks correct without listing a repo's whole history.
+// Tags are filtered by the repository's include/exclude/prerelease settings.
+func (c *Client) GetReleasesInRange(ctx context.Context, repository models.Repository, start, end time.Time) ([]models.Release, error) {
+	owner, repo, category := repository.Owner, repository.Repo, repository.Category
+	filter, err := newTagFilter(repository)
+	if err != nil {
+		return nil, err
+	}
+
 	var releases []models.Release
+	// Tags of every published release seen, including older ones on the
+	// last page, so each release can be classified against its predecessor
+	var history []string
 
 	opts := &github.ListOptions{PerPage: 100}
-	ghReleases, resp, err := c.gh.Repositories.ListReleases(ctx, owner, repo, opts)
-	if err != nil {
-		// Check for rate limit
-		if resp != nil && resp.StatusCode == 403 {
-			log.Printf("Rate limited for %s/%s, remaining: %d, reset: %v",
-				owner, repo, resp.Rate.Remaining, resp.Rate.Reset.Time)
+	for page := 1; page <= maxReleasePages; page++ {
+		var ghReleases []*github.RepositoryRelease
+		var resp *github.Response
+		err := c.call(ctx, func() (*github.Response, error) {
+			var err error
+			ghReleases, resp, err = c.gh.Repositories.ListReleases(ctx, owner, repo, opts)
+			return resp, err
+		})
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
-	}
 
-	for _, r := range ghReleases {
-		// Skip drafts
-		if r.GetDraft() {
-			continue
+		pageHasRecent := false
+		for _, r := range ghReleases {
+			// Ordering is by creation date, so a release created before the
+			// window may still have been published inside it
+			if !r.GetCreatedAt().Time.Before(start) {
+				pageHasRecent = true
+			}
+
+			// Skip drafts
+			if r.GetDraft() {
+				continue
+			}
+
+			if r.PublishedAt == nil || !filter.matches(r.GetTagName()) {
+				continue
+			}
+			history = append(history, r.GetTagName())
+
+			// Check if release is within the time range [start, end)
+			publishedAt := r.PublishedAt.Time
+			if !publishedAt.Before(start) {
+				pageHasRecent = true
+			}
+			if publishedAt.Before(start) || !publishedAt.Before(end) || !filter.allows(r.GetTagName(), r.GetPrerelease()) {
+				continue
+			}
+
+			releaseName := r.GetName()
+			if releaseName == "" {
+				releaseName = r.GetTagName()
+			}
+
+			releases = append(releases, models.Release{
+				ID:           r.GetID(),
+				RepoOwner:    owner,
+				RepoName:     repo,
+				TagName:      r.GetTagName(),
+				Name:         releaseName,
+				Body:         r.GetBody(),
+				URL:          r.GetHTMLURL(),
+				PublishedAt:  r.PublishedAt.Time,
+				Category:     category,
+				IsPrerelease: r.GetPrerelease(),
+			})
 		}
 
-		if r.PublishedAt == nil {
-			continue
+		if !pageHasRecent || resp == nil || resp.NextPage == 0 {
+			break
+		}
+		if page == maxReleasePages {
+			log.Printf("  %s/%s: stopped after %d pages of releases, older releases in the window are ignored", owner, repo, maxReleasePages)
+		}
+		opts.Page = resp.NextPage
+	}
+
+	version.Annotate(releases, history)
+	return releases, nil
+}
+
+func (c *Client) FetchAllReleases(ctx context.Context, repos []models.Repository) ([]models.Release, []SkippedRepo, error) {
+	oneWeekAgo := time.Now().AddDate(0, 0, -7)
+	return c.FetchReleasesInRange(ctx, repos, oneWeekAgo, time.Now())
+}
+
+// FetchReleasesInRange fetches all releases from all repos within the given
+// time range. Repos that failed after retries are returned as skipped.
+func (c *Client) FetchReleasesInRange(ctx context.Context, repos []models.Repository, start, end time.Time) ([]models.Release, []SkippedRepo, error) {
+	log.Printf("Fetching releases from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	results, err := c.fetchRepos(ctx, repos, func(models.Repository) time.Time { return start }, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.expandChangelogs(ctx, results)
+	return collectResults(repos, results)
+}
+
+// FetchNewReleases fetches, for every repo, the releases published since the
+// repo's cursor in the crawl state up to end (exclusive), and advances the
+// cursors to end. Repos without a cursor (first run, newly tracked) use
+// fallbackStart.
+func (c *Client) FetchNewReleases(ctx context.Context, repos []models.Repository, st *state.Store, fallbackStart, end time.Time) ([]models.Release, []SkippedRepo, error) {
+	log.Printf("Fetching releases since last crawl (edition %s, fallback %s) up to %s",
+		st.Edition(), fallbackStart.Format("2006-01-02"), end.Format("2006-01-02 15:04"))
+
+	startFor := func(repo models.Repository) time.Time {
+		if cursor, ok := st.RepoCursor(repo.Owner + "/" + repo.Repo); ok {
+			return cursor.Since()
 		}
+		return fallbackStart
+	}
 
-		// Check if release is within the time range
-		publishedAt := r.PublishedAt.Time
-		if publishedAt.Before(start) || publishedAt.After(end) {
+	results, err := c.fetchRepos(ctx, repos, startFor, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, repo := range repos {
+		if results[i].err != nil {
+			// Keep the old cursor so the next run retries the same window
 			continue
 		}
 
-		releaseName := r.GetName()
-		if releaseName == "" {
-			releaseName = r.GetTagName()
+		key := repo.Owner + "/" + repo.Repo
+		cursor, ok := st.RepoCursor(key)
+		start := startFor(repo)
+
+		next := cursor
+		next.CheckedAt = end
+		var fresh []models.Release
+		for _, r := range results[i].releases {
+			// The window start is inclusive, like the end of the previous
+			// window was exclusive; only the release the cursor points at was
+			// already reported
+			if ok && (isCursorRelease(r, cursor) || r.PublishedAt.Before(start)) {
+				continue
+			}
+			fresh = append(fresh, r)
+			if r.PublishedAt.After(next.LastPublishedAt) {
+				next.LastReleaseID = r.ID
+				next.LastTag = r.TagName
+				next.LastPublishedAt = r.PublishedAt
+			}
 		}
+		st.SetRepoCursor(key, next)
+		results[i].releases = fresh
+	}
+
+	c.expandChangelogs(ctx, results)
+	return collectResults(repos, results)
+}
 
-		releases = append(releases, models.Release{
-			RepoOwner:    owner,
-			RepoName:     repo,
-			TagName:      r.GetTagName(),
-			Name:         releaseName,
-			Body:         r.GetBody(),
-			URL:          r.GetHTMLURL(),
-			PublishedAt:  r.PublishedAt.Time,
-			Category:     category,
-			IsPrerelease: r.GetPrerelease(),
+// isCursorRelease reports whether r is the release the cursor points at.
+// Tag-based releases have no release ID and are matched by tag.
+func isCursorRelease(r models.Release, cursor state.RepoCursor) bool {
+	if r.ID != 0 {
+		return r.ID == cursor.LastReleaseID
+	}
+	return r.TagName == cursor.LastTag
+}
+
+// repoResult is the fetch outcome for one repository.
+type repoResult struct {
+	releases []models.Release
+	err      error
+}
+
+// fetchRepos fetches the releases in [startFor(repo), end) for every repo,
+// via batched GraphQL queries or per-repo REST calls on a bounded worker
+// pool. Results are indexed like repos.
+func (c *Client) fetchRepos(ctx context.Context, repos []models.Repository, startFor func(models.Repository) time.Time, end time.Time) ([]repoResult, error) {
+	results := make([]repoResult, len(repos))
+
+	if !c.useGraphQL {
+		log.Printf("Using REST (%d workers)", c.workers)
+		err := c.runPool(ctx, len(repos), func(i int) {
+			repo := repos[i]
+			log.Printf("[%d/%d] Fetching %s/%s...", i+1, len(repos), repo.Owner, repo.Repo)
+			releases, err := c.fetchRepo(ctx, repo, startFor(repo), end)
+			results[i] = repoResult{releases: releases, err: err}
 		})
+		return results, err
 	}
 
-	return releases, nil
+	// Tag-based repos need their own query; batch only the release-based ones
+	var releaseIdx, tagIdx []int
+	for i, repo := range repos {
+		if repo.Source == models.SourceTags {
+			tagIdx = append(tagIdx, i)
+		} else {
+			releaseIdx = append(releaseIdx, i)
+		}
+	}
+
+	batches := chunk(releaseIdx, releasesBatchSize)
+	log.Printf("Using GraphQL: %d batches of up to %d repositories, %d tag-based repositories (%d workers)",
+		len(batches), releasesBatchSize, len(tagIdx), c.workers)
+	err := c.runPool(ctx, len(batches)+len(tagIdx), func(b int) {
+		if b < len(batches) {
+			log.Printf("[batch %d/%d] Fetching releases for %d repositories...", b+1, len(batches), len(batches[b]))
+			c.fetchReleasesBatch(ctx, repos, batches[b], startFor, end, results)
+			return
+		}
+		i := tagIdx[b-len(batches)]
+		repo := repos[i]
+		log.Printf("[tags] Fetching %s/%s...", repo.Owner, repo.Repo)
+		releases, err := c.fetchRepo(ctx, repo, startFor(repo), end)
+		results[i] = repoResult{releases: releases, err: err}
+	})
+	return results, err
 }
 
-func (c *Client) FetchAllReleases(ctx context.Context, repos []models.Repository) ([]models.Release, error) {
-	oneWeekAgo := time.Now().AddDate(0, 0, -7)
-	return c.FetchReleasesInRange(ctx, repos, oneWeekAgo, time.Now())
+// fetchRepo fetches one repository's releases according to its source.
+func (c *Client) fetchRepo(ctx context.Context, repo models.Repository, start, end time.Time) ([]models.Release, error) {
+	if repo.Source == models.SourceTags {
+		return c.GetTagReleasesInRange(ctx, repo, start, end)
+	}
+	return c.GetReleasesInRange(ctx, repo, start, end)
 }
 
-// FetchReleasesInRange fetches all releases from all repos within the given time range
-func (c *Client) FetchReleasesInRange(ctx context.Context, repos []models.Repository, start, end time.Time) ([]models.Release, error) {
-	var allReleases []models.Release
+// runPool calls job(0..n-1) on c.workers goroutines and waits for them.
+func (c *Client) runPool(ctx context.Context, n int, job func(i int)) error {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				job(i)
+			}
+		}()
+	}
 
-	log.Printf("Fetching releases from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-	for i, repo := range repos {
-		log.Printf("[%d/%d] Fetching %s/%s...", i+1, len(repos), repo.Owner, repo.Repo)
+	return ctx.Err()
+}
 
-		releases, err := c.GetReleasesInRange(ctx, repo.Owner, repo.Repo, repo.Category, start, end)
-		if err != nil {
-			log.Printf("  Error: %v", err)
+// collectResults annotates and groups each repo's releases, flattens them in
+// repo order, logs them and reports failed repos as skipped instead of
+// aborting the run.
+func collectResults(repos []models.Repository, results []repoResult) ([]models.Release, []SkippedRepo, error) {
+	var allReleases []models.Release
+	var skipped []SkippedRepo
+	for i, repo := range repos {
+		name := repo.Owner + "/" + repo.Repo
+		if results[i].err != nil {
+			log.Printf("  Error fetching %s: %v", name, results[i].err)
+			skipped = append(skipped, SkippedRepo{Repo: name, Err: results[i].err})
 			continue
 		}
-
+		// Notes are parsed per release before grouping merges them
+		releasenotes.Annotate(results[i].releases)
+		releases := groupReleases(repo, results[i].releases)
 		if len(releases) > 0 {
-			log.Printf("  Found %d releases", len(releases))
+			log.Printf("  %s: %d releases", name, len(releases))
 			for _, r := range releases {
 				log.Printf("    - %s (%s)", r.TagName, r.PublishedAt.Format("2006-01-02"))
+				for _, comp := range r.Components {
+					log.Printf("      + %s", comp.TagName)
+				}
 			}
 		}
-
 		allReleases = append(allReleases, releases...)
+	}
 
-		// Small delay to avoid rate limiting
-		time.Sleep(100 * time.Millisecond)
+	if len(skipped) > 0 {
+		log.Printf("Skipped %d of %d repositories:", len(skipped), len(repos))
+		for _, s := range skipped {
+			log.Printf("  - %s", s)
+		}
 	}
 
-	return allReleases, nil
+	return allReleases, skipped, nil
 }
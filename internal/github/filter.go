@@ -0,0 +1,68 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
+)
+
+// tagFilter decides which tags of a repository are tracked, from the
+// include_tags, exclude_tags and track_prereleases settings.
+type tagFilter struct {
+	include     []*regexp.Regexp
+	exclude     []*regexp.Regexp
+	prereleases bool
+}
+
+func newTagFilter(repo models.Repository) (*tagFilter, error) {
+	f := &tagFilter{prereleases: repo.TrackPrereleases == nil || *repo.TrackPrereleases}
+	for _, p := range repo.IncludeTags {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include_tags pattern %q: %w", p, err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, p := range repo.ExcludeTags {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_tags pattern %q: %w", p, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+// matches reports whether tag passes the include and exclude patterns.
+func (f *tagFilter) matches(tag string) bool {
+	if len(f.include) > 0 {
+		included := false
+		for _, re := range f.include {
+			if re.MatchString(tag) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// allows reports whether a release with tag is tracked. Without
+// track_prereleases, both prerelease tags and releases GitHub flags as
+// prereleases are dropped; tags without a release pass prerelease false.
+func (f *tagFilter) allows(tag string, prerelease bool) bool {
+	if !f.matches(tag) {
+		return false
+	}
+	return f.prereleases || (!prerelease && !version.IsPrerelease(tag))
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+func TestTagFilterPrereleases(t *testing.T) {
+	off := false
+	tests := []struct {
+		name       string
+		track      *bool
+		tag        string
+		prerelease bool
+		want       bool
+	}{
+		{"stable", &off, "v1.2.3", false, true},
+		{"prerelease tag", &off, "v1.3.0-rc.1", false, false},
+		{"flagged by GitHub", &off, "v1.3.0", true, false},
+		{"tracked by default", nil, "v1.3.0-rc.1", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newTagFilter(models.Repository{Owner: "o", Repo: "r", TrackPrereleases: tt.track})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.allows(tt.tag, tt.prerelease); got != tt.want {
+				t.Errorf("allows(%q, %v) = %v, want %v", tt.tag, tt.prerelease, got, tt.want)
+			}
+		})
+	}
+}
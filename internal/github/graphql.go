@@ -0,0 +1,403 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
+)
+
+const (
+	graphqlURL = "https://api.github.com/graphql"
+
+	// releasesBatchSize repositories are queried per GraphQL request.
+	releasesBatchSize = 20
+	// releasesPerRepo is the number of most recent releases requested per
+	// repository. Repos that may have more in the window fall back to REST.
+	releasesPerRepo = 25
+	// statsBatchSize repositories are queried per stats request (each repo
+	// costs one history count and two search counts).
+	statsBatchSize = 25
+)
+
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphqlError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []graphqlError             `json:"errors"`
+}
+
+// aliasErrors maps the top-level alias of each error path to its message.
+func (r *graphqlResponse) aliasErrors() map[string]string {
+	out := make(map[string]string)
+	for _, e := range r.Errors {
+		if len(e.Path) == 0 {
+			continue
+		}
+		if alias, ok := e.Path[0].(string); ok {
+			out[alias] = e.Message
+		}
+	}
+	return out
+}
+
+// rateLimitError returns an error if GitHub rejected the query for rate limiting.
+func (r *graphqlResponse) rateLimitError() error {
+	for _, e := range r.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return fmt.Errorf("GraphQL rate limited: %s", e.Message)
+		}
+	}
+	return nil
+}
+
+// graphql posts a query and decodes the response. Partial results (data plus
+// per-alias errors) are returned without error; the caller maps errors to
+// repositories. Rate limits are handled like REST calls via the shared gate.
+func (c *Client) graphql(ctx context.Context, query string, vars map[string]interface{}) (*graphqlResponse, error) {
+	payload, err := json.Marshal(graphqlRequest{Query: query, Variables: vars})
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := c.gate.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", graphqlURL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.gate.observeHeaders(resp.Header)
+
+		switch {
+		case rateLimited(resp.StatusCode, resp.Header, body):
+			lastErr = fmt.Errorf("GraphQL rate limited (status %d)", resp.StatusCode)
+			c.gate.pauseUntil(retryAt(resp.Header, attempt), "GraphQL rate limit hit")
+			continue
+		case resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable:
+			// Usually a query timeout on GitHub's side; retry after a pause
+			lastErr = fmt.Errorf("GraphQL returned status %d", resp.StatusCode)
+			if err := sleepCtx(ctx, baseBackoff<<(attempt-1)); err != nil {
+				return nil, err
+			}
+			continue
+		case resp.StatusCode != http.StatusOK:
+			if len(body) > 200 {
+				body = body[:200]
+			}
+			return nil, fmt.Errorf("GraphQL returned status %d: %s", resp.StatusCode, body)
+		}
+
+		var out graphqlResponse
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("failed to decode GraphQL response: %w", err)
+		}
+		if rateErr := out.rateLimitError(); rateErr != nil {
+			lastErr = rateErr
+			c.gate.pauseUntil(retryAt(resp.Header, attempt), "GraphQL rate limit hit")
+			continue
+		}
+		if out.Data == nil {
+			if len(out.Errors) > 0 {
+				return nil, fmt.Errorf("GraphQL error: %s", out.Errors[0].Message)
+			}
+			return nil, fmt.Errorf("GraphQL response without data")
+		}
+		return &out, nil
+	}
+	return nil, lastErr
+}
+
+// rateLimited reports whether a failed response is a primary or secondary
+// rate limit. Other 403s (bad token, missing scope) are not worth retrying.
+func rateLimited(status int, h http.Header, body []byte) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if h.Get("X-RateLimit-Remaining") == "0" || h.Get("Retry-After") != "" {
+			return true
+		}
+		return strings.Contains(strings.ToLower(string(body)), "rate limit")
+	}
+	return false
+}
+
+// retryAt derives when to retry from Retry-After or X-RateLimit-Reset,
+// falling back to exponential backoff.
+func retryAt(h http.Header, attempt int) time.Time {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0).Add(time.Second)
+		}
+	}
+	return time.Now().Add(baseBackoff << (attempt - 1))
+}
+
+// batchIndexes splits 0..n-1 into consecutive chunks of at most size.
+func batchIndexes(n, size int) [][]int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return chunk(idx, size)
+}
+
+// chunk splits idx into consecutive chunks of at most size.
+func chunk(idx []int, size int) [][]int {
+	var out [][]int
+	for start := 0; start < len(idx); start += size {
+		end := start + size
+		if end > len(idx) {
+			end = len(idx)
+		}
+		out = append(out, idx[start:end])
+	}
+	return out
+}
+
+// repoVars declares $oN/$nN variables for each repository in the batch.
+func repoVars(repos []models.Repository, batch []int, vars map[string]interface{}) []string {
+	decls := make([]string, 0, 2*len(batch))
+	for j, i := range batch {
+		vars[fmt.Sprintf("o%d", j)] = repos[i].Owner
+		vars[fmt.Sprintf("n%d", j)] = repos[i].Repo
+		decls = append(decls, fmt.Sprintf("$o%d: String!, $n%d: String!", j, j))
+	}
+	return decls
+}
+
+type gqlRelease struct {
+	DatabaseID   int64      `json:"databaseId"`
+	TagName      string     `json:"tagName"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	URL          string     `json:"url"`
+	PublishedAt  *time.Time `json:"publishedAt"`
+	IsPrerelease bool       `json:"isPrerelease"`
+	IsDraft      bool       `json:"isDraft"`
+}
+
+type gqlReleasesRepo struct {
+	Releases struct {
+		Nodes []gqlRelease `json:"nodes"`
+	} `json:"releases"`
+}
+
+// fetchReleasesBatch queries the latest releases of a batch of repositories
+// in one GraphQL request and stores per-repo results in results.
+func (c *Client) fetchReleasesBatch(ctx context.Context, repos []models.Repository, batch []int, startFor func(models.Repository) time.Time, end time.Time, results []repoResult) {
+	vars := make(map[string]interface{})
+	decls := repoVars(repos, batch, vars)
+
+	var q strings.Builder
+	fmt.Fprintf(&q, "query(%s) {\n", strings.Join(decls, ", "))
+	for j := range batch {
+		fmt.Fprintf(&q, `  r%d: repository(owner: $o%d, name: $n%d) {
+    releases(first: %d, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { databaseId tagName name description url publishedAt isPrerelease isDraft }
+    }
+  }
+`, j, j, j, releasesPerRepo)
+	}
+	q.WriteString("}\n")
+
+	resp, err := c.graphql(ctx, q.String(), vars)
+	if err != nil {
+		for _, i := range batch {
+			results[i] = repoResult{err: err}
+		}
+		return
+	}
+	errs := resp.aliasErrors()
+
+	for j, i := range batch {
+		repo := repos[i]
+		alias := fmt.Sprintf("r%d", j)
+		start := startFor(repo)
+
+		raw, ok := resp.Data[alias]
+		if msg, failed := errs[alias]; failed || !ok || string(raw) == "null" {
+			if msg == "" {
+				msg = "repository not found"
+			}
+			results[i] = repoResult{err: fmt.Errorf("%s", msg)}
+			continue
+		}
+
+		var data gqlReleasesRepo
+		if err := json.Unmarshal(raw, &data); err != nil {
+			results[i] = repoResult{err: fmt.Errorf("failed to decode releases: %w", err)}
+			continue
+		}
+
+		// A full page whose oldest release is still in the window may hide
+		// more releases; let the REST path page through those
+		nodes := data.Releases.Nodes
+		if len(nodes) == releasesPerRepo {
+			oldest := nodes[len(nodes)-1]
+			if oldest.PublishedAt == nil || !oldest.PublishedAt.Before(start) {
+				releases, err := c.GetReleasesInRange(ctx, repo, start, end)
+				results[i] = repoResult{releases: releases, err: err}
+				continue
+			}
+		}
+
+		filter, err := newTagFilter(repo)
+		if err != nil {
+			results[i] = repoResult{err: err}
+			continue
+		}
+
+		var releases []models.Release
+		var history []string
+		for _, r := range nodes {
+			if r.IsDraft || r.PublishedAt == nil || !filter.matches(r.TagName) {
+				continue
+			}
+			history = append(history, r.TagName)
+			if r.PublishedAt.Before(start) || !r.PublishedAt.Before(end) || !filter.allows(r.TagName, r.IsPrerelease) {
+				continue
+			}
+			name := r.Name
+			if name == "" {
+				name = r.TagName
+			}
+			releases = append(releases, models.Release{
+				ID:           r.DatabaseID,
+				RepoOwner:    repo.Owner,
+				RepoName:     repo.Repo,
+				TagName:      r.TagName,
+				Name:         name,
+				Body:         r.Description,
+				URL:          r.URL,
+				PublishedAt:  *r.PublishedAt,
+				Category:     repo.Category,
+				IsPrerelease: r.IsPrerelease,
+			})
+		}
+		version.Annotate(releases, history)
+		results[i] = repoResult{releases: releases}
+	}
+}
+
+type gqlStatsRepo struct {
+	DefaultBranchRef *struct {
+		Target struct {
+			History *struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+type gqlSearchCount struct {
+	IssueCount int `json:"issueCount"`
+}
+
+// fetchStatsBatch collects commit, merged-PR and opened-PR counts for a batch
+// of repositories in one GraphQL request.
+func (c *Client) fetchStatsBatch(ctx context.Context, repos []models.Repository, batch []int, start, end time.Time) ([]models.RepoStats, error) {
+	vars := map[string]interface{}{
+		"since": start.UTC().Format(time.RFC3339),
+		"until": end.UTC().Format(time.RFC3339),
+	}
+	decls := append([]string{"$since: GitTimestamp!", "$until: GitTimestamp!"}, repoVars(repos, batch, vars)...)
+
+	dateRange := fmt.Sprintf("%s..%s", start.UTC().Format("2006-01-02T15:04:05Z"), end.UTC().Format("2006-01-02T15:04:05Z"))
+
+	var q strings.Builder
+	for j, i := range batch {
+		slug := repos[i].Owner + "/" + repos[i].Repo
+		vars[fmt.Sprintf("qm%d", j)] = fmt.Sprintf("repo:%s is:pr is:merged merged:%s", slug, dateRange)
+		vars[fmt.Sprintf("qo%d", j)] = fmt.Sprintf("repo:%s is:pr created:%s", slug, dateRange)
+		decls = append(decls, fmt.Sprintf("$qm%d: String!, $qo%d: String!", j, j))
+
+		fmt.Fprintf(&q, `  r%d: repository(owner: $o%d, name: $n%d) {
+    defaultBranchRef { target { ... on Commit { history(since: $since, until: $until) { totalCount } } } }
+  }
+  m%d: search(query: $qm%d, type: ISSUE, first: 1) { issueCount }
+  p%d: search(query: $qo%d, type: ISSUE, first: 1) { issueCount }
+`, j, j, j, j, j, j, j)
+	}
+	query := fmt.Sprintf("query(%s) {\n%s}\n", strings.Join(decls, ", "), q.String())
+
+	resp, err := c.graphql(ctx, query, vars)
+	if err != nil {
+		return nil, err
+	}
+	errs := resp.aliasErrors()
+
+	var out []models.RepoStats
+	for j, i := range batch {
+		repo := repos[i]
+		alias := fmt.Sprintf("r%d", j)
+		if msg, failed := errs[alias]; failed {
+			// Missing or inaccessible repo — drop it like the REST path does
+			log.Printf("  stats error for %s/%s: %s", repo.Owner, repo.Repo, msg)
+			continue
+		}
+
+		s := models.RepoStats{
+			RepoOwner:  repo.Owner,
+			RepoName:   repo.Repo,
+			Category:   repo.Category,
+			WindowFrom: start,
+			WindowTo:   end,
+		}
+
+		var r gqlStatsRepo
+		if raw, ok := resp.Data[alias]; ok && json.Unmarshal(raw, &r) == nil &&
+			r.DefaultBranchRef != nil && r.DefaultBranchRef.Target.History != nil {
+			s.Commits = r.DefaultBranchRef.Target.History.TotalCount
+		}
+
+		var merged, opened gqlSearchCount
+		if raw, ok := resp.Data[fmt.Sprintf("m%d", j)]; ok && json.Unmarshal(raw, &merged) == nil {
+			s.MergedPRs = merged.IssueCount
+		}
+		if raw, ok := resp.Data[fmt.Sprintf("p%d", j)]; ok && json.Unmarshal(raw, &opened) == nil {
+			s.OpenedPRs = opened.IssueCount
+		}
+
+		if s.Commits > 0 || s.MergedPRs > 0 || s.OpenedPRs > 0 {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,176 @@
+package github
+
+import (
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
+)
+
+// maxGroupedItems caps merged note and security lists of a grouped release.
+const maxGroupedItems = 10
+
+// releaseRank orders release types by significance.
+var releaseRank = map[string]int{
+	version.Major:      4,
+	version.Minor:      3,
+	version.Patch:      2,
+	version.Prerelease: 1,
+}
+
+// groupReleases collapses the releases of one repository published on the
+// same (UTC) day into a single release with components, as configured by
+// repo.Grouping. Releases are expected in the order they were listed.
+func groupReleases(repo models.Repository, releases []models.Release) []models.Release {
+	mode := repo.Grouping
+	if mode == "" {
+		mode = models.GroupingAuto
+	}
+	if mode == models.GroupingNone || len(releases) < 2 {
+		return releases
+	}
+
+	var days []string
+	byDay := make(map[string][]models.Release)
+	for _, r := range releases {
+		day := r.PublishedAt.UTC().Format("2006-01-02")
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], r)
+	}
+
+	var grouped []models.Release
+	for _, day := range days {
+		members := byDay[day]
+		if len(members) < 2 || (mode == models.GroupingAuto && countComponents(members) < 2) {
+			grouped = append(grouped, members...)
+			continue
+		}
+		grouped = append(grouped, mergeReleases(members))
+	}
+	return grouped
+}
+
+// componentOf returns the tag prefix that identifies a release's component.
+// Tags that are not versions are their own component.
+func componentOf(tag string) string {
+	if v, ok := version.Parse(tag); ok {
+		return v.Prefix
+	}
+	return tag
+}
+
+func countComponents(releases []models.Release) int {
+	seen := make(map[string]bool)
+	for _, r := range releases {
+		seen[componentOf(r.TagName)] = true
+	}
+	return len(seen)
+}
+
+// mergeReleases folds members into the primary release: the stable release
+// of the main component (no tag prefix) if there is one, else the most
+// significant stable release, else the prerelease of the main component,
+// else the first. Stable members always rank above prereleases, so a
+// prerelease is only the primary of a group without stable releases.
+func mergeReleases(members []models.Release) models.Release {
+	primary := 0
+	best := -1
+	for i, r := range members {
+		score := releaseRank[r.ReleaseType]
+		if componentOf(r.TagName) == "" {
+			score += 10
+		}
+		if isStable(r) {
+			score += 100
+		}
+		if score > best {
+			primary, best = i, score
+		}
+	}
+
+	merged := members[primary]
+	merged.Components = nil
+	merged.SecurityFixes = append([]models.SecurityFix(nil), merged.SecurityFixes...)
+	merged.SecurityNotes = append([]string(nil), merged.SecurityNotes...)
+	if merged.Notes != nil {
+		notes := *merged.Notes
+		merged.Notes = &notes
+	}
+
+	stable := isStable(merged)
+	for i, r := range members {
+		// A stable group is as significant as its most significant stable
+		// member; a prerelease primary keeps its own type
+		if stable && isStable(r) && releaseRank[r.ReleaseType] > releaseRank[merged.ReleaseType] {
+			merged.ReleaseType = r.ReleaseType
+		}
+		if stable && isStable(r) && r.Bump != version.Prerelease && releaseRank[r.Bump] > releaseRank[merged.Bump] {
+			merged.Bump = r.Bump
+		}
+		if i == primary {
+			continue
+		}
+
+		merged.Components = append(merged.Components, models.ReleaseComponent{
+			TagName:      r.TagName,
+			Name:         r.Name,
+			URL:          r.URL,
+			IsPrerelease: r.IsPrerelease,
+			ReleaseType:  r.ReleaseType,
+		})
+		merged.SecurityFixes = mergeFixes(merged.SecurityFixes, r.SecurityFixes)
+		merged.SecurityNotes = mergeStrings(merged.SecurityNotes, r.SecurityNotes)
+		if r.Notes != nil {
+			if merged.Notes == nil {
+				merged.Notes = &models.ReleaseNotes{Format: r.Notes.Format}
+			}
+			merged.Notes.Breaking = mergeStrings(merged.Notes.Breaking, r.Notes.Breaking)
+			merged.Notes.Deprecations = mergeStrings(merged.Notes.Deprecations, r.Notes.Deprecations)
+			merged.Notes.Features = mergeStrings(merged.Notes.Features, r.Notes.Features)
+			merged.Notes.Fixes = mergeStrings(merged.Notes.Fixes, r.Notes.Fixes)
+			merged.Notes.Other = mergeStrings(merged.Notes.Other, r.Notes.Other)
+		}
+	}
+
+	return merged
+}
+
+// isStable reports whether r is neither flagged nor tagged as a prerelease.
+func isStable(r models.Release) bool {
+	return !r.IsPrerelease && r.ReleaseType != version.Prerelease
+}
+
+func mergeStrings(dst, src []string) []string {
+	for _, s := range src {
+		if len(dst) >= maxGroupedItems {
+			break
+		}
+		dup := false
+		for _, d := range dst {
+			if d == s {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+func mergeFixes(dst, src []models.SecurityFix) []models.SecurityFix {
+	for _, f := range src {
+		dup := false
+		for _, d := range dst {
+			if d.ID == f.ID {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, f)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
+)
+
+func TestMergeReleasesPrefersStableMembers(t *testing.T) {
+	day := time.Date(2026, 10, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		members    []models.Release
+		wantTag    string
+		wantType   string
+		wantPrerel bool
+	}{
+		{
+			name: "stable component over main prerelease",
+			members: []models.Release{
+				{TagName: "v2.0.0-rc.1", ReleaseType: version.Prerelease, Bump: version.Prerelease, IsPrerelease: true, PublishedAt: day},
+				{TagName: "helm-chart-1.5.3", ReleaseType: version.Patch, Bump: version.Patch, PublishedAt: day},
+			},
+			wantTag:  "helm-chart-1.5.3",
+			wantType: version.Patch,
+		},
+		{
+			name: "stable main over stable component",
+			members: []models.Release{
+				{TagName: "helm-chart-2.0.0", ReleaseType: version.Major, Bump: version.Major, PublishedAt: day},
+				{TagName: "v1.4.2", ReleaseType: version.Patch, Bump: version.Patch, PublishedAt: day},
+			},
+			wantTag:  "v1.4.2",
+			wantType: version.Major,
+		},
+		{
+			name: "prerelease primary keeps its type",
+			members: []models.Release{
+				{TagName: "helm-chart-2.0.0-beta.1", ReleaseType: version.Prerelease, Bump: version.Prerelease, IsPrerelease: true, PublishedAt: day},
+				{TagName: "v2.0.0-rc.1", ReleaseType: version.Prerelease, Bump: version.Prerelease, IsPrerelease: true, PublishedAt: day},
+			},
+			wantTag:    "v2.0.0-rc.1",
+			wantType:   version.Prerelease,
+			wantPrerel: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeReleases(tt.members)
+			if merged.TagName != tt.wantTag {
+				t.Errorf("primary = %s, want %s", merged.TagName, tt.wantTag)
+			}
+			if merged.ReleaseType != tt.wantType || merged.Bump != tt.wantType {
+				t.Errorf("ReleaseType = %s, Bump = %s, want %s", merged.ReleaseType, merged.Bump, tt.wantType)
+			}
+			if merged.IsPrerelease != tt.wantPrerel {
+				t.Errorf("IsPrerelease = %v, want %v", merged.IsPrerelease, tt.wantPrerel)
+			}
+			if len(merged.Components) != len(tt.members)-1 {
+				t.Errorf("got %d components, want %d", len(merged.Components), len(tt.members)-1)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+const (
+	// maxAttempts bounds retries of a single API call on rate limiting.
+	maxAttempts = 5
+	// baseBackoff is the first delay for secondary rate limits without
+	// Retry-After; it doubles on every attempt.
+	baseBackoff = 10 * time.Second
+	// minRemaining pauses all workers before the primary quota runs dry,
+	// so in-flight requests of other workers still succeed.
+	minRemaining = 10
+)
+
+// rateGate is shared by all workers of a Client. When any response shows
+// the quota is (nearly) exhausted, the gate closes for everybody until the
+// reset time instead of letting each worker burn through failing requests.
+type rateGate struct {
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// wait blocks until the gate is open or ctx is done.
+func (g *rateGate) wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		d := time.Until(g.pausedUntil)
+		g.mu.Unlock()
+		if d <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pauseUntil closes the gate until t (never shortens an existing pause).
+func (g *rateGate) pauseUntil(t time.Time, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if t.After(g.pausedUntil) {
+		g.pausedUntil = t
+		log.Printf("GitHub %s — pausing all requests until %s (%s)",
+			reason, t.Format("15:04:05"), time.Until(t).Round(time.Second))
+	}
+}
+
+// observe inspects the X-RateLimit-Remaining/Reset headers of a response.
+func (g *rateGate) observe(resp *github.Response) {
+	if resp == nil || resp.Rate.Limit == 0 {
+		return
+	}
+	if resp.Rate.Remaining < minRemaining {
+		// One extra second absorbs clock skew between us and GitHub
+		g.pauseUntil(resp.Rate.Reset.Time.Add(time.Second), "rate limit nearly exhausted")
+	}
+}
+
+// observeHeaders is observe for raw HTTP responses (GraphQL).
+func (g *rateGate) observeHeaders(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	if remaining < minRemaining {
+		g.pauseUntil(time.Unix(reset, 0).Add(time.Second), "rate limit nearly exhausted")
+	}
+}
+
+// call runs fn, retrying on primary and secondary rate limits and transient
+// server errors. The gate is honoured before every attempt.
+func (c *Client) call(ctx context.Context, fn func() (*github.Response, error)) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if werr := c.gate.wait(ctx); werr != nil {
+			return werr
+		}
+
+		var resp *github.Response
+		resp, err = fn()
+		c.gate.observe(resp)
+		if err == nil {
+			return nil
+		}
+
+		var rateErr *github.RateLimitError
+		var abuseErr *github.AbuseRateLimitError
+		switch {
+		case errors.As(err, &rateErr):
+			c.gate.pauseUntil(rateErr.Rate.Reset.Time.Add(time.Second), "rate limit exhausted")
+		case errors.As(err, &abuseErr):
+			delay := baseBackoff << (attempt - 1)
+			if abuseErr.RetryAfter != nil {
+				delay = *abuseErr.RetryAfter
+			}
+			// Secondary limits apply to the whole token, so back off globally
+			c.gate.pauseUntil(time.Now().Add(delay), "secondary rate limit hit")
+		case resp != nil && (resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable):
+			if werr := sleepCtx(ctx, baseBackoff<<(attempt-1)); werr != nil {
+				return werr
+			}
+		default:
+			return err
+		}
+	}
+	return err
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
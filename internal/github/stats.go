@@ -69,12 +69,17 @@ func (c *Client) GetRepoStats(ctx context.Context, owner, repo, category string,
 }
 
 // FetchAllStats iterates over repos and collects stats for the given window.
-// Respects search-API rate limits with a conservative delay (~2.5s per repo
+// With GraphQL enabled, many repos are counted per request. The REST path
+// respects search-API rate limits with a conservative delay (~2.5s per repo
 // ≈ 24 req/min, 2 search calls each -> stays under 30/min).
 func (c *Client) FetchAllStats(ctx context.Context, repos []models.Repository, start, end time.Time) []models.RepoStats {
 	log.Printf("Collecting repo stats from %s to %s for %d repos...",
 		start.Format("2006-01-02"), end.Format("2006-01-02"), len(repos))
 
+	if c.useGraphQL {
+		return c.fetchAllStatsGraphQL(ctx, repos, start, end)
+	}
+
 	out := make([]models.RepoStats, 0, len(repos))
 	for i, repo := range repos {
 		log.Printf("[%d/%d] stats: %s/%s", i+1, len(repos), repo.Owner, repo.Repo)
@@ -91,3 +96,21 @@ func (c *Client) FetchAllStats(ctx context.Context, repos []models.Repository, s
 	}
 	return out
 }
+
+// fetchAllStatsGraphQL collects stats in batches of statsBatchSize repos per
+// GraphQL query. A failed batch is logged and skipped.
+func (c *Client) fetchAllStatsGraphQL(ctx context.Context, repos []models.Repository, start, end time.Time) []models.RepoStats {
+	batches := batchIndexes(len(repos), statsBatchSize)
+
+	out := make([]models.RepoStats, 0, len(repos))
+	for b, batch := range batches {
+		log.Printf("[batch %d/%d] stats for %d repos", b+1, len(batches), len(batch))
+		stats, err := c.fetchStatsBatch(ctx, repos, batch, start, end)
+		if err != nil {
+			log.Printf("  error: %v", err)
+			continue
+		}
+		out = append(out, stats...)
+	}
+	return out
+}
@@ -0,0 +1,266 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/version"
+)
+
+// tagsPerPage is the page size for the tag refs query.
+const tagsPerPage = 100
+
+// maxTagPages caps how far back tag pagination goes.
+const maxTagPages = 10
+
+// datedTag is a version tag with the date it was created.
+type datedTag struct {
+	version.Version
+	date time.Time
+}
+
+type gqlTagTarget struct {
+	Typename      string `json:"__typename"`
+	CommittedDate string `json:"committedDate"`
+	Tagger        *struct {
+		Date string `json:"date"`
+	} `json:"tagger"`
+	Target *struct {
+		CommittedDate string `json:"committedDate"`
+	} `json:"target"`
+}
+
+// date prefers the tagger date of annotated tags, else the commit date.
+func (t gqlTagTarget) date() time.Time {
+	raw := t.CommittedDate
+	if t.Tagger != nil && t.Tagger.Date != "" {
+		raw = t.Tagger.Date
+	} else if t.Target != nil {
+		raw = t.Target.CommittedDate
+	}
+	d, _ := time.Parse(time.RFC3339, raw)
+	return d
+}
+
+type gqlTagRefs struct {
+	Repository *struct {
+		Refs struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []struct {
+				Name   string       `json:"name"`
+				Target gqlTagTarget `json:"target"`
+			} `json:"nodes"`
+		} `json:"refs"`
+	} `json:"repository"`
+}
+
+const tagRefsQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: $first, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        target {
+          __typename
+          ... on Commit { committedDate }
+          ... on Tag { tagger { date } target { ... on Commit { committedDate } } }
+        }
+      }
+    }
+  }
+}`
+
+// GetTagReleasesInRange detects semver tags dated within [start, end) for
+// repositories that do not publish GitHub Releases, and reports each as a
+// models.Release whose URL compares it with the previous version. Tags are
+// listed through GraphQL, or through REST when GraphQL is disabled.
+func (c *Client) GetTagReleasesInRange(ctx context.Context, repository models.Repository, start, end time.Time) ([]models.Release, error) {
+	owner, repo, category := repository.Owner, repository.Repo, repository.Category
+	filter, err := newTagFilter(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []datedTag
+	if c.useGraphQL {
+		tags, err = c.graphqlTags(ctx, owner, repo, start, filter)
+	} else {
+		tags, err = c.restTags(ctx, owner, repo, start, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]string, 0, len(tags))
+	for _, t := range tags {
+		history = append(history, t.Raw)
+	}
+
+	var releases []models.Release
+	for _, t := range tags {
+		if t.date.IsZero() || t.date.Before(start) || !t.date.Before(end) || !filter.allows(t.Raw, false) {
+			continue
+		}
+		releases = append(releases, models.Release{
+			RepoOwner:    owner,
+			RepoName:     repo,
+			TagName:      t.Raw,
+			Name:         t.Raw,
+			PublishedAt:  t.date,
+			Category:     category,
+			IsPrerelease: t.Prerelease,
+		})
+	}
+
+	// The predecessor found by Annotate shares the tag prefix, so the
+	// compare link stays within one component of a monorepo.
+	version.Annotate(releases, history)
+	for i := range releases {
+		r := &releases[i]
+		r.URL = fmt.Sprintf("https://github.com/%s/%s/tree/%s", owner, repo, r.TagName)
+		if r.PreviousTag != "" {
+			r.URL = fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", owner, repo, r.PreviousTag, r.TagName)
+		}
+	}
+
+	return releases, nil
+}
+
+// graphqlTags lists the version tags matching filter with their dates,
+// newest first, until tags predate start.
+func (c *Client) graphqlTags(ctx context.Context, owner, repo string, start time.Time, filter *tagFilter) ([]datedTag, error) {
+	var tags []datedTag
+	var after interface{}
+
+	// Refs are ordered by commit date; walk until tags predate the window.
+	// One extra page keeps the predecessors of in-window tags available.
+	extraPage := true
+	for page := 1; page <= maxTagPages; page++ {
+		resp, err := c.graphql(ctx, tagRefsQuery, map[string]interface{}{
+			"owner": owner,
+			"name":  repo,
+			"first": tagsPerPage,
+			"after": after,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if msg, failed := resp.aliasErrors()["repository"]; failed {
+			return nil, fmt.Errorf("%s", msg)
+		}
+
+		var data gqlTagRefs
+		if err := json.Unmarshal(resp.Data["repository"], &data.Repository); err != nil || data.Repository == nil {
+			return nil, fmt.Errorf("repository not found")
+		}
+
+		refs := data.Repository.Refs
+		pageHasRecent := false
+		for _, n := range refs.Nodes {
+			v, ok := version.Parse(n.Name)
+			if !ok {
+				continue
+			}
+			t := datedTag{Version: v, date: n.Target.date()}
+			if !t.date.Before(start) {
+				pageHasRecent = true
+			}
+			// Filtered tags still count for pagination, not as predecessors
+			if filter.matches(n.Name) {
+				tags = append(tags, t)
+			}
+		}
+
+		if !refs.PageInfo.HasNextPage {
+			break
+		}
+		if !pageHasRecent {
+			if !extraPage {
+				break
+			}
+			extraPage = false
+		}
+		after = refs.PageInfo.EndCursor
+	}
+	return tags, nil
+}
+
+// restTags lists the version tags matching filter through REST. Tag lists
+// carry no dates, so the commit date is looked up per tag: within each
+// version line (prefix, major and minor) from the highest version down
+// until one predates start. Older tags keep a zero date and only serve as
+// predecessors. Unlike GraphQL, annotated tags are dated by their commit.
+func (c *Client) restTags(ctx context.Context, owner, repo string, start time.Time, filter *tagFilter) ([]datedTag, error) {
+	var tags []datedTag
+	shas := make(map[string]string)
+
+	opts := &github.ListOptions{PerPage: tagsPerPage}
+	for page := 1; page <= maxTagPages; page++ {
+		var ghTags []*github.RepositoryTag
+		var resp *github.Response
+		err := c.call(ctx, func() (*github.Response, error) {
+			var err error
+			ghTags, resp, err = c.gh.Repositories.ListTags(ctx, owner, repo, opts)
+			return resp, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range ghTags {
+			v, ok := version.Parse(t.GetName())
+			if !ok || !filter.matches(t.GetName()) {
+				continue
+			}
+			tags = append(tags, datedTag{Version: v})
+			shas[v.Raw] = t.GetCommit().GetSHA()
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Compare(tags[j].Version) > 0
+	})
+
+	dates := make(map[string]time.Time)
+	done := make(map[string]bool)
+	for i := range tags {
+		line := fmt.Sprintf("%s%d.%d", tags[i].Prefix, tags[i].Major, tags[i].Minor)
+		if done[line] {
+			continue
+		}
+		sha := shas[tags[i].Raw]
+		date, ok := dates[sha]
+		if !ok {
+			var commit *github.RepositoryCommit
+			err := c.call(ctx, func() (*github.Response, error) {
+				var resp *github.Response
+				var err error
+				commit, resp, err = c.gh.Repositories.GetCommit(ctx, owner, repo, sha, nil)
+				return resp, err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get commit of tag %s: %w", tags[i].Raw, err)
+			}
+			date = commit.GetCommit().GetCommitter().GetDate().Time
+			dates[sha] = date
+		}
+		tags[i].date = date
+		if date.Before(start) {
+			done[line] = true
+		}
+	}
+	return tags, nil
+}
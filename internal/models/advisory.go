@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Advisory is a published GitHub security advisory (GHSA) of a tracked
+// repository. Fields are copied from the advisory as-is so the newsletter can
+// report them neutrally.
+type Advisory struct {
+	GHSAID          string                  `json:"ghsa_id"`
+	CVEID           string                  `json:"cve_id,omitempty"`
+	RepoOwner       string                  `json:"repo_owner"`
+	RepoName        string                  `json:"repo_name"`
+	Category        string                  `json:"category,omitempty"`
+	Summary         string                  `json:"summary"`
+	Severity        string                  `json:"severity,omitempty"`
+	CVSSScore       float64                 `json:"cvss_score,omitempty"`
+	CVSSVector      string                  `json:"cvss_vector,omitempty"`
+	CWEs            []string                `json:"cwes,omitempty"`
+	URL             string                  `json:"url"`
+	PublishedAt     time.Time               `json:"published_at"`
+	Vulnerabilities []AdvisoryVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// AdvisoryVulnerability is one affected package of an advisory.
+type AdvisoryVulnerability struct {
+	Ecosystem          string `json:"ecosystem,omitempty"`
+	Package            string `json:"package,omitempty"`
+	VulnerableVersions string `json:"vulnerable_versions,omitempty"`
+	PatchedVersions    string `json:"patched_versions,omitempty"`
+}
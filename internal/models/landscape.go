@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// LandscapeChanges lists how the CNCF projects changed between two runs of
+// the landscape sync.
+type LandscapeChanges struct {
+	SyncedAt time.Time       `json:"synced_at"`
+	Joined   []ProjectChange `json:"joined"`
+	Promoted []ProjectChange `json:"promoted"`
+	Archived []ProjectChange `json:"archived"`
+	Removed  []ProjectChange `json:"removed"`
+}
+
+// Empty reports whether no project changed.
+func (c *LandscapeChanges) Empty() bool {
+	return c == nil || len(c.Joined)+len(c.Promoted)+len(c.Archived)+len(c.Removed) == 0
+}
+
+// ProjectChange is one CNCF project whose status changed. From is empty for
+// projects that joined, To is empty for projects that were removed.
+type ProjectChange struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+	Category string `json:"category,omitempty"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+}
@@ -9,17 +9,89 @@ type NewsItem struct {
 	Description string    `json:"description"`
 	PublishedAt time.Time `json:"published_at"`
 	Category    string    `json:"category"`
+	Language    string    `json:"language,omitempty"`
+	// Content is the plain-text article body a feed carries, if any.
+	Content    string   `json:"content,omitempty"`
+	Authors    []string `json:"authors,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	// Excerpt and WordCount describe the linked article's main text, set
+	// when article extraction is enabled in the crawler.
+	Excerpt   string `json:"excerpt,omitempty"`
+	WordCount int    `json:"word_count,omitempty"`
+	// Points and Comments are the community engagement (Hacker News).
+	Points   int `json:"points,omitempty"`
+	Comments int `json:"comments,omitempty"`
+	// Score is the relevance computed by news.Scorer; items below the
+	// configured cutoff are left out of the newsletter prompt.
+	Score float64 `json:"score,omitempty"`
+	// Scored is set when the crawler scored the item, so a score of 0 is
+	// told apart from a file crawled without scoring.
+	Scored bool `json:"scored,omitempty"`
+	// Attributions lists every source that carried the item when several
+	// sources reported the same story and were merged into one item.
+	Attributions []NewsAttribution `json:"attributions,omitempty"`
+}
+
+// NewsAttribution is one source that reported a merged news item.
+type NewsAttribution struct {
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+}
+
+// Feed health statuses.
+const (
+	FeedOK          = "ok"
+	FeedNotModified = "not_modified"
+	FeedFailed      = "failed"
+)
+
+// FeedHealth is the outcome of fetching one news source in a crawl, with
+// enough history to spot sources that have been broken for a while.
+type FeedHealth struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Kind      string    `json:"kind"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	ItemCount int       `json:"item_count"`
+	CheckedAt time.Time `json:"checked_at"`
+	// LastSuccessAt is the last crawl that reached the source, also
+	// carried over from earlier reports.
+	LastSuccessAt       time.Time `json:"last_success_at,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
 }
 
 type RSSSource struct {
 	Name string `yaml:"name"`
 	URL  string `yaml:"url"`
+	// Boilerplate are regexes removed from the item texts line by line, on
+	// top of common footers like "The post X appeared first on Y".
+	Boilerplate []string `yaml:"boilerplate,omitempty"`
 }
 
+// ScrapeSource describes an HTML page without a feed. All selectors are CSS
+// selectors; everything except Selector is evaluated relative to one item.
 type ScrapeSource struct {
-	Name     string `yaml:"name"`
-	URL      string `yaml:"url"`
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Selector matches one news item (e.g. an article teaser).
 	Selector string `yaml:"selector"`
+	// FallbackSelectors are tried in order when Selector matches nothing.
+	FallbackSelectors   []string `yaml:"fallback_selectors,omitempty"`
+	TitleSelector       string   `yaml:"title_selector,omitempty"`
+	LinkSelector        string   `yaml:"link_selector,omitempty"`
+	DescriptionSelector string   `yaml:"description_selector,omitempty"`
+	DateSelector        string   `yaml:"date_selector,omitempty"`
+	// DateAttr is the attribute holding the date (default: datetime). The
+	// element text is used when the attribute is missing.
+	DateAttr    string   `yaml:"date_attr,omitempty"`
+	DateLayouts []string `yaml:"date_layouts,omitempty"`
+	// BaseURL resolves relative links (default: URL).
+	BaseURL        string            `yaml:"base_url,omitempty"`
+	Headers        map[string]string `yaml:"headers,omitempty"`
+	Language       string            `yaml:"language,omitempty"`
+	MinTitleLength int               `yaml:"min_title_length,omitempty"`
 }
 
 type HackerNewsConfig struct {
@@ -27,8 +99,32 @@ type HackerNewsConfig struct {
 	Keywords []string `yaml:"keywords"`
 }
 
+// ScoringConfig weighs how relevant a news item is for the newsletter.
+type ScoringConfig struct {
+	// MinScore is the cutoff below which items are not sent to the model.
+	MinScore float64 `yaml:"min_score"`
+	// MaxItems caps the items sent to the model, highest score first (0: no cap).
+	MaxItems int `yaml:"max_items,omitempty"`
+	// Keywords maps a word or phrase to its weight. A match in the title
+	// counts fully, a match in the description half.
+	Keywords map[string]float64 `yaml:"keywords"`
+	// ProjectWeight is added when a tracked project is mentioned.
+	ProjectWeight float64 `yaml:"project_weight"`
+	// IgnoreProjects lists tracked project names that are ordinary words
+	// ("kind", "Harbor") and therefore do not count as mentions.
+	IgnoreProjects []string `yaml:"ignore_projects,omitempty"`
+	// SourceWeights multiply the score of a source's items (default 1).
+	SourceWeights map[string]float64 `yaml:"source_weights,omitempty"`
+	// PointsWeight and CommentsWeight scale log10(1+n) of the engagement.
+	PointsWeight   float64 `yaml:"points_weight"`
+	CommentsWeight float64 `yaml:"comments_weight"`
+	// CoverageWeight is added for every other source that reported the story.
+	CoverageWeight float64 `yaml:"coverage_weight"`
+}
+
 type NewsSourceConfig struct {
 	RSSFeeds      []RSSSource      `yaml:"rss_feeds"`
 	ScrapeSources []ScrapeSource   `yaml:"scrape_sources"`
 	HackerNews    HackerNewsConfig `yaml:"hackernews"`
+	Scoring       ScoringConfig    `yaml:"scoring"`
 }
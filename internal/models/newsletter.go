@@ -11,6 +11,9 @@ type Newsletter struct {
 	Highlights []string   `json:"highlights"`
 	Releases   []Release  `json:"releases"`
 	NewsItems  []NewsItem `json:"news_items"`
+	Advisories []Advisory `json:"advisories,omitempty"`
+
+	LandscapeChanges *LandscapeChanges `json:"landscape_changes,omitempty"`
 }
 
 type DraftMetadata struct {
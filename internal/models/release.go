@@ -3,6 +3,7 @@ package models
 import "time"
 
 type Release struct {
+	ID           int64     `json:"id,omitempty"`
 	RepoOwner    string    `json:"repo_owner"`
 	RepoName     string    `json:"repo_name"`
 	TagName      string    `json:"tag_name"`
@@ -12,14 +13,96 @@ type Release struct {
 	PublishedAt  time.Time `json:"published_at"`
 	Category     string    `json:"category"`
 	IsPrerelease bool      `json:"is_prerelease,omitempty"`
+	// ReleaseType is major, minor, patch or prerelease, from the tag shape.
+	ReleaseType string `json:"release_type,omitempty"`
+	// PreviousTag is the preceding version of the same component, if known.
+	PreviousTag string `json:"previous_tag,omitempty"`
+	// Bump is the component that changed relative to PreviousTag.
+	Bump string `json:"bump,omitempty"`
+	// SecurityFixes are the CVE/GHSA identifiers referenced in Body.
+	SecurityFixes []SecurityFix `json:"security_fixes,omitempty"`
+	// SecurityNotes are the lines of the release notes' security section.
+	SecurityNotes []string `json:"security_notes,omitempty"`
+	// ExpandedBody holds the changelog section a link-only Body points to.
+	ExpandedBody string `json:"expanded_body,omitempty"`
+	// NotesURL is the changelog location ExpandedBody was taken from.
+	NotesURL string `json:"notes_url,omitempty"`
+	// Notes is the structured content of NotesBody, nil for unstructured notes.
+	Notes *ReleaseNotes `json:"notes,omitempty"`
+	// Components are the other releases of the same repository and day that
+	// were grouped into this one (see Repository.Grouping).
+	Components []ReleaseComponent `json:"components,omitempty"`
 }
 
+// ReleaseComponent is one release folded into a grouped Release.
+type ReleaseComponent struct {
+	TagName      string `json:"tag_name"`
+	Name         string `json:"name,omitempty"`
+	URL          string `json:"url"`
+	IsPrerelease bool   `json:"is_prerelease,omitempty"`
+	ReleaseType  string `json:"release_type,omitempty"`
+}
+
+// NotesBody returns the full release notes: the expanded changelog section
+// when Body only links to it, else Body.
+func (r Release) NotesBody() string {
+	if r.ExpandedBody != "" {
+		return r.ExpandedBody
+	}
+	return r.Body
+}
+
+// ReleaseNotes groups the entries of a release body by kind of change.
+type ReleaseNotes struct {
+	// Format is the recognized layout, e.g. "github" or "keepachangelog".
+	Format       string   `json:"format,omitempty"`
+	Breaking     []string `json:"breaking,omitempty"`
+	Deprecations []string `json:"deprecations,omitempty"`
+	Features     []string `json:"features,omitempty"`
+	Fixes        []string `json:"fixes,omitempty"`
+	Other        []string `json:"other,omitempty"`
+}
+
+// SecurityFix is a vulnerability identifier referenced by a release.
+type SecurityFix struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Release sources for Repository.Source.
+const (
+	// SourceReleases tracks GitHub Releases (default).
+	SourceReleases = "releases"
+	// SourceTags tracks semver git tags for projects without GitHub Releases.
+	SourceTags = "tags"
+)
+
+// Release grouping modes for Repository.Grouping.
+const (
+	// GroupingAuto groups a day's releases when they belong to different
+	// components (tag prefixes such as "api/" or "helm-chart-"). Default.
+	GroupingAuto = "auto"
+	// GroupingDay groups all releases of a day.
+	GroupingDay = "day"
+	// GroupingNone keeps every release separate.
+	GroupingNone = "none"
+)
+
 type Repository struct {
 	Owner      string `yaml:"owner" json:"owner"`
 	Repo       string `yaml:"repo" json:"repo"`
 	Name       string `yaml:"name" json:"name"`
 	Category   string `yaml:"category" json:"category"`
 	CNCFStatus string `yaml:"cncf_status,omitempty" json:"cncf_status,omitempty"`
+	Source     string `yaml:"source,omitempty" json:"source,omitempty"`
+	Grouping   string `yaml:"grouping,omitempty" json:"grouping,omitempty"`
+	// IncludeTags limits tracking to tags matching any of these regexes.
+	IncludeTags []string `yaml:"include_tags,omitempty" json:"include_tags,omitempty"`
+	// ExcludeTags drops tags matching any of these regexes.
+	ExcludeTags []string `yaml:"exclude_tags,omitempty" json:"exclude_tags,omitempty"`
+	// TrackPrereleases set to false drops prerelease tags and releases GitHub
+	// flags as prereleases (default: tracked).
+	TrackPrereleases *bool `yaml:"track_prereleases,omitempty" json:"track_prereleases,omitempty"`
 }
 
 type RepositoryConfig struct {
@@ -27,5 +110,23 @@ type RepositoryConfig struct {
 }
 
 type AdditionalReposConfig struct {
-	AdditionalRepositories []Repository `yaml:"additional_repositories"`
+	AdditionalRepositories []Repository         `yaml:"additional_repositories"`
+	Overrides              []RepositoryOverride `yaml:"overrides,omitempty"`
+}
+
+// RepositoryOverride corrects entries synced from the CNCF landscape. It
+// matches entries by project name or by owner/repo and can exclude them,
+// patch their fields, or add further repositories for the same project.
+type RepositoryOverride struct {
+	// Project matches the entry name, case-insensitively.
+	Project string `yaml:"project,omitempty"`
+	// Repo matches "owner/repo", case-insensitively.
+	Repo string `yaml:"repo,omitempty"`
+	// Exclude drops the matched entries.
+	Exclude bool `yaml:"exclude,omitempty"`
+	// Set replaces every field of the matched entries that is set here.
+	Set *Repository `yaml:"set,omitempty"`
+	// ExtraRepos are tracked in addition; empty name, category and
+	// cncf_status are taken from the matched entry.
+	ExtraRepos []Repository `yaml:"extra_repos,omitempty"`
 }
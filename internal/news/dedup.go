@@ -0,0 +1,406 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// titleSimilarity is the share of title words two items must have in common
+// to count as the same story.
+const titleSimilarity = 0.75
+
+// minFuzzyTitleWords keeps short titles from being merged on a few common
+// words; shorter titles must match exactly.
+const minFuzzyTitleWords = 4
+
+// trackingParams are query parameters that only identify the referrer.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true, "ref": true,
+	"ref_src": true, "sc_channel": true, "sc_campaign": true, "trk": true,
+	"amp": true, "outputtype": true, "__twitter_impression": true,
+}
+
+var (
+	titleWordRe = regexp.MustCompile(`[\pL\pN]+(?:\.[\pN]+)*`)
+	digitRe     = regexp.MustCompile(`\pN`)
+)
+
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"to": true, "in": true, "on": true, "for": true, "with": true, "is": true,
+	"are": true, "at": true, "by": true, "from": true, "now": true, "its": true,
+}
+
+// Deduplicator merges news items that several sources reported: items with
+// the same canonical URL, and items whose titles are nearly identical.
+type Deduplicator struct {
+	client          *http.Client
+	followRedirects bool
+	workers         int
+}
+
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		workers: DefaultFeedWorkers,
+	}
+}
+
+// FollowRedirects resolves every item URL to where it redirects (feed
+// proxies, link shorteners) before comparing URLs. This costs one HEAD
+// request per URL.
+func (d *Deduplicator) FollowRedirects(enabled bool) {
+	d.followRedirects = enabled
+}
+
+// Deduplicate returns the items with duplicates merged, in the order each
+// story first appeared. A merged item keeps the fields of the original
+// publisher (news sources over community sites, then the earliest item)
+// and lists all sources in Attributions.
+func (d *Deduplicator) Deduplicate(ctx context.Context, items []models.NewsItem) []models.NewsItem {
+	if len(items) == 0 {
+		return items
+	}
+
+	urls := make([]string, len(items))
+	for i, item := range items {
+		urls[i] = CanonicalURL(item.URL)
+	}
+	if d.followRedirects {
+		resolved := d.resolveAll(ctx, urls)
+		for i, u := range urls {
+			if r, ok := resolved[u]; ok {
+				urls[i] = r
+			}
+		}
+	}
+
+	// Union-find over items: same URL key or similar title
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		// The earlier item stays the root so clusters keep their position
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	byURL := make(map[string]int)
+	words := make([][]string, len(items))
+	for i, item := range items {
+		if key := urlKey(urls[i]); key != "" {
+			if j, ok := byURL[key]; ok {
+				union(i, j)
+			} else {
+				byURL[key] = i
+			}
+		}
+		words[i] = titleWords(item.Title, item.Source)
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if find(i) != find(j) && similarTitles(words[i], words[j]) {
+				union(i, j)
+			}
+		}
+	}
+
+	clusters := make(map[int][]int)
+	var roots []int
+	for i := range items {
+		root := find(i)
+		if _, ok := clusters[root]; !ok {
+			roots = append(roots, root)
+		}
+		clusters[root] = append(clusters[root], i)
+	}
+
+	result := make([]models.NewsItem, 0, len(roots))
+	for _, root := range roots {
+		members := clusters[root]
+		merged := make([]models.NewsItem, len(members))
+		for k, i := range members {
+			merged[k] = items[i]
+			merged[k].URL = urls[i]
+		}
+		result = append(result, mergeNewsItems(merged))
+	}
+	return result
+}
+
+// CanonicalURL removes what makes the same article look like different
+// URLs: tracking parameters, AMP variants, fragments and trailing slashes.
+// Unparseable URLs are returned unchanged.
+func CanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Host = strings.TrimPrefix(u.Host, "amp.")
+
+	path := strings.TrimSuffix(u.Path, "/")
+	for _, suffix := range []string{"/amp", ".amp", "/amp.html"} {
+		path = strings.TrimSuffix(path, suffix)
+	}
+	if strings.HasPrefix(path, "/amp/") {
+		path = strings.TrimPrefix(path, "/amp")
+	}
+	u.Path = path
+	u.RawPath = ""
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	// Encode sorts the parameters, so their order does not matter either
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// urlKey is the comparison key of a canonical URL: scheme and a leading
+// "www." do not distinguish articles.
+func urlKey(canonical string) string {
+	u, err := url.Parse(canonical)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(canonical)
+	}
+	key := strings.TrimPrefix(u.Host, "www.") + u.EscapedPath()
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// titleWords returns the significant words of a title, without a trailing
+// " - Source" or " | Source" that some feeds append.
+func titleWords(title, source string) []string {
+	lower := strings.ToLower(title)
+	if source != "" {
+		for _, sep := range []string{" - ", " | ", " – ", " — "} {
+			lower = strings.TrimSuffix(lower, sep+strings.ToLower(source))
+		}
+	}
+
+	var words []string
+	seen := make(map[string]bool)
+	for _, w := range titleWordRe.FindAllString(lower, -1) {
+		if titleStopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// similarTitles compares the word sets of two titles. Titles that differ
+// in a word with a digit (a version, a year) are different stories.
+func similarTitles(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+
+	inA := make(map[string]bool, len(a))
+	for _, w := range a {
+		inA[w] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, w := range b {
+		inB[w] = true
+	}
+
+	common := 0
+	for _, w := range a {
+		if inB[w] {
+			common++
+		} else if digitRe.MatchString(w) {
+			return false
+		}
+	}
+	for _, w := range b {
+		if !inA[w] && digitRe.MatchString(w) {
+			return false
+		}
+	}
+
+	if common == len(a) && common == len(b) {
+		return true
+	}
+	if min(len(a), len(b)) < minFuzzyTitleWords {
+		return false
+	}
+	union := len(a) + len(b) - common
+	return float64(common)/float64(union) >= titleSimilarity
+}
+
+// mergeNewsItems merges one cluster of duplicates into a single item.
+func mergeNewsItems(items []models.NewsItem) models.NewsItem {
+	if len(items) == 1 && len(items[0].Attributions) == 0 {
+		return items[0]
+	}
+
+	primary := 0
+	for i, item := range items[1:] {
+		if preferNewsItem(item, items[primary]) {
+			primary = i + 1
+		}
+	}
+
+	merged := items[primary]
+	var attributions []models.NewsAttribution
+	seen := make(map[string]bool)
+	addAttribution := func(a models.NewsAttribution) {
+		key := a.Source + "\x00" + urlKey(a.URL)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		attributions = append(attributions, a)
+	}
+
+	// The primary source is listed first
+	order := append([]models.NewsItem{items[primary]}, items[:primary]...)
+	order = append(order, items[primary+1:]...)
+	for _, item := range order {
+		if len(item.Attributions) > 0 {
+			for _, a := range item.Attributions {
+				addAttribution(a)
+			}
+		} else {
+			addAttribution(models.NewsAttribution{Source: item.Source, URL: item.URL, Title: item.Title})
+		}
+	}
+
+	// Engagement usually comes from the community copy of the story
+	for _, item := range items {
+		merged.Points = max(merged.Points, item.Points)
+		merged.Comments = max(merged.Comments, item.Comments)
+	}
+
+	// Without a description of its own, the most detailed one is used
+	if merged.Description == "" {
+		for _, item := range items {
+			if len(item.Description) > len(merged.Description) {
+				merged.Description = item.Description
+			}
+		}
+	}
+
+	merged.Attributions = nil
+	if len(attributions) > 1 {
+		merged.Attributions = attributions
+	}
+	return merged
+}
+
+// preferNewsItem reports whether a is a better primary item than b: the
+// original publisher over community sites, then the earlier report.
+func preferNewsItem(a, b models.NewsItem) bool {
+	aCommunity, bCommunity := a.Category == "community", b.Category == "community"
+	if aCommunity != bCommunity {
+		return !aCommunity
+	}
+	return a.PublishedAt.Before(b.PublishedAt)
+}
+
+// resolveAll follows the redirects of every distinct URL concurrently and
+// returns the final URLs that differ from the input.
+func (d *Deduplicator) resolveAll(ctx context.Context, urls []string) map[string]string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if !seen[u] && strings.HasPrefix(u, "http") {
+			seen[u] = true
+			unique = append(unique, u)
+		}
+	}
+
+	resolved := make(map[string]string)
+	var mu sync.Mutex
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < d.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				if final := d.resolve(ctx, u); final != u {
+					mu.Lock()
+					resolved[u] = final
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, u := range unique {
+		select {
+		case jobs <- u:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return resolved
+}
+
+// resolve returns the canonical URL a request for u ends up at, or u when
+// the request fails.
+func (d *Deduplicator) resolve(ctx context.Context, u string) string {
+	final := u
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return u
+		}
+		req.Header.Set("User-Agent", "LWCN-Bot/1.0 (Last Week in Cloud Native)")
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return u
+		}
+		resp.Body.Close()
+		// Some servers reject HEAD; retry those with GET
+		if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+			continue
+		}
+		if resp.StatusCode < 400 {
+			final = CanonicalURL(resp.Request.URL.String())
+		}
+		break
+	}
+	return final
+}
@@ -0,0 +1,114 @@
+package news
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://example.com/post/?utm_source=rss&utm_medium=feed", "https://example.com/post"},
+		{"https://example.com/post?fbclid=abc&id=7&gclid=x", "https://example.com/post?id=7"},
+		{"https://example.com/post?b=2&a=1", "https://example.com/post?a=1&b=2"},
+		{"https://EXAMPLE.com/post#comments", "https://example.com/post"},
+		{"https://amp.example.com/post", "https://example.com/post"},
+		{"https://example.com/post/amp/", "https://example.com/post"},
+		{"https://example.com/post.amp", "https://example.com/post"},
+		{"https://example.com/amp/post", "https://example.com/post"},
+		{"https://example.com/post?amp=1", "https://example.com/post"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.raw); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestURLKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"http://www.example.com/post", "https://example.com/post", true},
+		{"https://www.example.com/post?utm_campaign=x", "https://example.com/post/", true},
+		{"https://example.com/post?id=1", "https://example.com/post?id=2", false},
+		{"https://example.com/post", "https://blog.example.com/post", false},
+	}
+	for _, tt := range tests {
+		ka, kb := urlKey(CanonicalURL(tt.a)), urlKey(CanonicalURL(tt.b))
+		if (ka == kb) != tt.same {
+			t.Errorf("urlKey(%q) = %q, urlKey(%q) = %q, same = %v, want %v", tt.a, ka, tt.b, kb, ka == kb, tt.same)
+		}
+	}
+}
+
+func TestSimilarTitles(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		sourceB string
+		want    bool
+	}{
+		{"Kubernetes 1.34 Released", "Kubernetes 1.34 released", "", true},
+		{"Kubernetes 1.34 released with new scheduling features", "Kubernetes 1.34 released with new scheduling features - The New Stack", "The New Stack", true},
+		{"CNCF announces the graduation of the Cilium project today", "CNCF announces graduation of Cilium project", "", true},
+		{"Kubernetes 1.34 released", "Kubernetes 1.35 released", "", false},
+		{"Istio 1.27 brings ambient mode improvements for everyone", "Istio 1.28 brings ambient mode improvements for everyone", "", false},
+		{"KubeCon 2025 recap", "KubeCon 2026 recap", "", false},
+		{"Helm release", "Helm security release", "", false},
+		{"Envoy Gateway adds rate limiting", "Linkerd adds mesh expansion support", "", false},
+	}
+	for _, tt := range tests {
+		got := similarTitles(titleWords(tt.a, ""), titleWords(tt.b, tt.sourceB))
+		if got != tt.want {
+			t.Errorf("similarTitles(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDeduplicateMergesAttributions(t *testing.T) {
+	day := time.Date(2026, 10, 6, 8, 0, 0, 0, time.UTC)
+	items := []models.NewsItem{
+		{Title: "Kubernetes 1.34 released", URL: "https://news.ycombinator.com/item?id=1", Source: "Hacker News", Category: "community", PublishedAt: day, Points: 312, Comments: 80},
+		{Title: "Kubernetes v1.34: Of Wind & Will", URL: "https://www.kubernetes.io/blog/2026/10/06/kubernetes-v1-34-release/?utm_source=rss", Source: "Kubernetes Blog", Category: "news", PublishedAt: day.Add(2 * time.Hour)},
+		{Title: "Kubernetes v1.34 is out", URL: "https://kubernetes.io/blog/2026/10/06/kubernetes-v1-34-release/", Source: "Reddit r/kubernetes", Category: "community", PublishedAt: day.Add(time.Hour)},
+		{Title: "Kubernetes 1.34 released", URL: "https://example.com/k8s-134", Source: "Example Blog", Category: "news", PublishedAt: day.Add(3 * time.Hour), Description: "All about the release"},
+		{Title: "Prometheus 3.5 released", URL: "https://prometheus.io/blog/3-5", Source: "Prometheus Blog", Category: "news", PublishedAt: day},
+	}
+
+	got := NewDeduplicator().Deduplicate(context.Background(), items)
+	if len(got) != 3 {
+		t.Fatalf("got %d items, want 3: %+v", len(got), got)
+	}
+
+	// Same title: the news source wins over Hacker News despite being later
+	first := got[0]
+	if first.Source != "Example Blog" || first.URL != "https://example.com/k8s-134" {
+		t.Errorf("primary = %s %s, want Example Blog", first.Source, first.URL)
+	}
+	if first.Points != 312 || first.Comments != 80 {
+		t.Errorf("engagement = %d/%d, want the Hacker News numbers", first.Points, first.Comments)
+	}
+	if len(first.Attributions) != 2 || first.Attributions[0].Source != "Example Blog" || first.Attributions[1].Source != "Hacker News" {
+		t.Errorf("Attributions = %+v, want Example Blog then Hacker News", first.Attributions)
+	}
+
+	// Same canonical URL with www and tracking parameters
+	second := got[1]
+	if second.Source != "Kubernetes Blog" || second.URL != "https://www.kubernetes.io/blog/2026/10/06/kubernetes-v1-34-release" {
+		t.Errorf("primary = %s %s, want the Kubernetes Blog", second.Source, second.URL)
+	}
+	if len(second.Attributions) != 2 || second.Attributions[1].Source != "Reddit r/kubernetes" {
+		t.Errorf("Attributions = %+v", second.Attributions)
+	}
+
+	if got[2].Source != "Prometheus Blog" || len(got[2].Attributions) != 0 {
+		t.Errorf("unrelated item = %+v, want it unchanged", got[2])
+	}
+}
@@ -0,0 +1,339 @@
+package news
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+const (
+	// DefaultArticleCacheDir is where extracted articles are cached.
+	DefaultArticleCacheDir = ".cache/articles"
+	// maxArticleBytes bounds how much of a page is read.
+	maxArticleBytes = 2 << 20
+	// maxExcerptLength bounds the excerpt stored on a news item.
+	maxExcerptLength = 600
+	// minArticleWords is the least text a page needs to count as an article.
+	minArticleWords = 50
+	// transientFailureTTL is how long a timeout, 429 or 5xx is cached
+	// before the article is fetched again.
+	transientFailureTTL = 6 * time.Hour
+)
+
+// boilerplateRe matches class and id values of page parts that are not
+// article text.
+var boilerplateRe = regexp.MustCompile(`(?i)\b(comments?|share|sharing|social|related|recommended|sidebar|newsletter|subscribe|signup|cookie|consent|promo|advert|ads?|sponsor|footer|header|nav|navigation|menu|breadcrumbs?|author-bio|tags|pagination|popup|modal)\b`)
+
+// contentHintRe marks blocks that look like boilerplate by name but hold
+// the content, like "post-header" wrappers around the whole article.
+var contentHintRe = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text|blog`)
+
+// extractedArticle is the cached result of extracting one URL. Failures are
+// cached too, so a rerun does not hit broken pages again; transient ones
+// only until RetryAfter.
+type extractedArticle struct {
+	URL        string    `json:"url"`
+	Excerpt    string    `json:"excerpt,omitempty"`
+	WordCount  int       `json:"word_count,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	RetryAfter time.Time `json:"retry_after,omitempty"`
+	FetchedAt  time.Time `json:"fetched_at"`
+}
+
+// expired reports whether a cached transient failure is due for a retry.
+func (a extractedArticle) expired(now time.Time) bool {
+	return !a.RetryAfter.IsZero() && !now.Before(a.RetryAfter)
+}
+
+// fetchError is a failed article fetch. Permanent failures (a 404, a page
+// that is no article) are cached for good.
+type fetchError struct {
+	err       error
+	permanent bool
+}
+
+func (e *fetchError) Error() string { return e.err.Error() }
+
+func (e *fetchError) Unwrap() error { return e.err }
+
+// Extractor fetches the article behind each news item and stores a plain
+// text excerpt and the word count of its main text on the item.
+type Extractor struct {
+	client   *http.Client
+	cacheDir string
+	workers  int
+}
+
+func NewExtractor(cacheDir string) *Extractor {
+	return &Extractor{
+		client:   &http.Client{Timeout: 20 * time.Second},
+		cacheDir: cacheDir,
+		workers:  DefaultFeedWorkers,
+	}
+}
+
+// SetWorkers sets how many articles are fetched concurrently.
+func (e *Extractor) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	e.workers = n
+}
+
+// Extract sets Excerpt and WordCount on the items for which want returns
+// true (all items when want is nil). Pages without article text leave the
+// item unchanged and are only counted in the log.
+func (e *Extractor) Extract(ctx context.Context, items []models.NewsItem, want func(models.NewsItem) bool) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	fetched, cached, failed := 0, 0, 0
+
+	for w := 0; w < e.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				article, fromCache := e.article(ctx, items[i].URL)
+
+				mu.Lock()
+				if fromCache {
+					cached++
+				} else {
+					fetched++
+				}
+				if article.Error != "" {
+					failed++
+				}
+				mu.Unlock()
+
+				items[i].Excerpt = article.Excerpt
+				items[i].WordCount = article.WordCount
+			}
+		}()
+	}
+
+feed:
+	for i, item := range items {
+		if want != nil && !want(item) {
+			continue
+		}
+		if !extractable(item.URL) {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	log.Printf("Article extraction: %d fetched, %d from cache, %d without article text", fetched, cached, failed)
+}
+
+// extractable skips URLs that are not articles, like discussion pages.
+func extractable(u string) bool {
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return false
+	}
+	return !strings.Contains(u, "news.ycombinator.com/")
+}
+
+// article returns the extracted article for u from the cache or the web.
+func (e *Extractor) article(ctx context.Context, u string) (extractedArticle, bool) {
+	path := e.cachePath(u)
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var article extractedArticle
+			if json.Unmarshal(data, &article) == nil && article.URL == u && !article.expired(time.Now()) {
+				return article, true
+			}
+		}
+	}
+
+	article := extractedArticle{URL: u, FetchedAt: time.Now().UTC()}
+	text, err := e.fetchText(ctx, u)
+	if err != nil {
+		article.Error = err.Error()
+		var fetchErr *fetchError
+		if !errors.As(err, &fetchErr) || !fetchErr.permanent {
+			article.RetryAfter = article.FetchedAt.Add(transientFailureTTL)
+		}
+	} else {
+		article.WordCount = len(strings.Fields(text))
+		article.Excerpt = excerpt(text, maxExcerptLength)
+	}
+
+	// Interrupted fetches are not cached so a rerun tries them again
+	if path != "" && ctx.Err() == nil {
+		if data, err := json.MarshalIndent(article, "", "  "); err == nil {
+			if err := os.MkdirAll(e.cacheDir, 0755); err == nil {
+				os.WriteFile(path, data, 0644)
+			}
+		}
+	}
+	return article, false
+}
+
+func (e *Extractor) cachePath(u string) string {
+	if e.cacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(e.cacheDir, hex.EncodeToString(sum[:12])+".json")
+}
+
+func (e *Extractor) fetchText(ctx context.Context, u string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range defaultScrapeHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Client errors other than rate limits will not go away
+		permanent := resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests
+		return "", &fetchError{err: fmt.Errorf("%s returned status %d", u, resp.StatusCode), permanent: permanent}
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return "", &fetchError{err: fmt.Errorf("%s is %s, not an HTML page", u, ct), permanent: true}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxArticleBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", u, err)
+	}
+
+	text := ArticleText(doc)
+	if len(strings.Fields(text)) < minArticleWords {
+		return "", &fetchError{err: fmt.Errorf("no article text found on %s", u), permanent: true}
+	}
+	return text, nil
+}
+
+// ArticleText extracts the main text of a page, readability style: page
+// chrome and boilerplate blocks are removed, then the element with the most
+// paragraph text and the fewest links wins. Paragraphs, headings and list
+// items of that element are returned one per line.
+func ArticleText(doc *goquery.Document) string {
+	doc.Find("script, style, noscript, template, iframe, svg, canvas, form, button, nav, header, footer, aside, figure").Remove()
+	pageText := len(cleanText(doc.Find("body").Text()))
+	doc.Find("[class], [id], [role]").Each(func(_ int, sel *goquery.Selection) {
+		if sel.Is("html, body, article, main") {
+			return
+		}
+		// Page wrappers ("site has-header") hold the article itself
+		if sel.Find("article, main").Length() > 0 || len(cleanText(sel.Text()))*2 > pageText {
+			return
+		}
+		if role, _ := sel.Attr("role"); role == "navigation" || role == "banner" || role == "contentinfo" || role == "complementary" {
+			sel.Remove()
+			return
+		}
+		class, _ := sel.Attr("class")
+		id, _ := sel.Attr("id")
+		names := class + " " + id
+		if boilerplateRe.MatchString(names) && !contentHintRe.MatchString(names) {
+			sel.Remove()
+		}
+	})
+
+	best := bestContainer(doc)
+	if best == nil {
+		return ""
+	}
+
+	var lines []string
+	best.Find("p, h2, h3, h4, li, pre, blockquote").Each(func(_ int, sel *goquery.Selection) {
+		// Nested blocks are covered by their parent
+		if sel.ParentsFiltered("p, li, pre, blockquote").Length() > 0 {
+			return
+		}
+		if line := cleanText(sel.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	})
+	return strings.Join(lines, "\n")
+}
+
+// bestContainer scores every element by the text of its direct paragraphs,
+// discounted by its link density. <article> and <main> get a head start.
+func bestContainer(doc *goquery.Document) *goquery.Selection {
+	var best *goquery.Selection
+	bestScore := 0.0
+
+	doc.Find("article, main, section, div, td").Each(func(_ int, sel *goquery.Selection) {
+		textLen := 0
+		sel.ChildrenFiltered("p, pre, blockquote, ul, ol, h2, h3").Each(func(_ int, p *goquery.Selection) {
+			textLen += len(cleanText(p.Text()))
+		})
+		if textLen == 0 {
+			return
+		}
+
+		total := len(cleanText(sel.Text()))
+		links := 0
+		sel.Find("a").Each(func(_ int, a *goquery.Selection) {
+			links += len(cleanText(a.Text()))
+		})
+		density := 0.0
+		if total > 0 {
+			density = float64(links) / float64(total)
+		}
+
+		score := float64(textLen) * (1 - density)
+		if sel.Is("article, main") {
+			score *= 1.5
+		}
+		if score > bestScore {
+			best, bestScore = sel, score
+		}
+	})
+	return best
+}
+
+// excerpt returns the start of text up to limit bytes, cut after a sentence
+// where possible and otherwise at a word boundary.
+func excerpt(text string, limit int) string {
+	text = cleanText(text)
+	if len(text) <= limit {
+		return text
+	}
+	cut := strings.ToValidUTF8(text[:limit], "")
+	end := -1
+	for _, stop := range []string{". ", "! ", "? "} {
+		end = max(end, strings.LastIndex(cut, stop))
+	}
+	if end > len(cut)/2 {
+		return cut[:end+1]
+	}
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "..."
+}
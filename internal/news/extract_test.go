@@ -0,0 +1,74 @@
+package news
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractorCachesOnlyPermanentFailures(t *testing.T) {
+	article := "<html><body><article><p>" + strings.Repeat("Cilium adds multi-pool IPAM support. ", 20) + "</p></article></body></html>"
+	status := map[string]int{"/gone": http.StatusNotFound, "/busy": http.StatusServiceUnavailable}
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if code, ok := status[r.URL.Path]; ok {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(article))
+	}))
+	defer srv.Close()
+
+	e := NewExtractor(t.TempDir())
+	ctx := context.Background()
+
+	gone, _ := e.article(ctx, srv.URL+"/gone")
+	if gone.Error == "" || !gone.RetryAfter.IsZero() {
+		t.Errorf("404: %+v, want a permanent failure", gone)
+	}
+	busy, _ := e.article(ctx, srv.URL+"/busy")
+	if busy.Error == "" || busy.RetryAfter.IsZero() {
+		t.Errorf("503: %+v, want a failure with a retry time", busy)
+	}
+
+	// Both failures are served from the cache until the retry time
+	if _, cached := e.article(ctx, srv.URL+"/gone"); !cached {
+		t.Error("404 not served from the cache")
+	}
+	if _, cached := e.article(ctx, srv.URL+"/busy"); !cached {
+		t.Error("503 not served from the cache before its retry time")
+	}
+
+	// Once the page is back and the retry time has passed, it is fetched again
+	delete(status, "/busy")
+	if !busy.expired(busy.RetryAfter) || busy.expired(busy.FetchedAt) {
+		t.Error("expired does not follow RetryAfter")
+	}
+	busy.RetryAfter = time.Now().Add(-time.Minute)
+	writeCachedArticle(t, e, busy)
+	again, cached := e.article(ctx, srv.URL+"/busy")
+	if cached || again.Error != "" || again.WordCount == 0 {
+		t.Errorf("after the retry time: %+v (cached %v), want a fresh article", again, cached)
+	}
+	if requests["/gone"] != 1 || requests["/busy"] != 2 {
+		t.Errorf("requests = %v", requests)
+	}
+}
+
+func writeCachedArticle(t *testing.T, e *Extractor, article extractedArticle) {
+	t.Helper()
+	data, err := json.Marshal(article)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(e.cachePath(article.URL), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"time"
 
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/window"
 )
 
 type HackerNewsClient struct {
 	client *http.Client
+	window window.Window
 }
 
 type hnSearchResponse struct {
@@ -34,19 +36,29 @@ type hnHit struct {
 }
 
 func NewHackerNewsClient() *HackerNewsClient {
+	now := time.Now()
 	return &HackerNewsClient{
 		client: &http.Client{Timeout: 30 * time.Second},
+		window: window.Window{Start: now.AddDate(0, 0, -7), End: now},
 	}
 }
 
+// UseWindow restricts results to stories created within w.
+func (c *HackerNewsClient) UseWindow(w window.Window) {
+	c.window = w
+}
+
+// numericFilter returns the Algolia numericFilters value for the window.
+func (c *HackerNewsClient) numericFilter() string {
+	return url.QueryEscape(fmt.Sprintf("created_at_i>=%d,created_at_i<%d", c.window.Start.Unix(), c.window.End.Unix()))
+}
+
 func (c *HackerNewsClient) Search(ctx context.Context, keywords []string) ([]models.NewsItem, error) {
 	var allItems []models.NewsItem
-	oneWeekAgo := time.Now().AddDate(0, 0, -7)
-	timestamp := oneWeekAgo.Unix()
 
 	// Strategy 1: Search by individual keywords
 	for _, keyword := range keywords {
-		items, err := c.searchKeyword(ctx, keyword, timestamp)
+		items, err := c.searchKeyword(ctx, keyword)
 		if err != nil {
 			log.Printf("HN search for '%s' failed: %v", keyword, err)
 			continue
@@ -63,7 +75,7 @@ func (c *HackerNewsClient) Search(ctx context.Context, keywords []string) ([]mod
 		"devops platform",
 	}
 	for _, query := range combinedQueries {
-		items, err := c.searchKeyword(ctx, query, timestamp)
+		items, err := c.searchKeyword(ctx, query)
 		if err != nil {
 			continue
 		}
@@ -72,7 +84,7 @@ func (c *HackerNewsClient) Search(ctx context.Context, keywords []string) ([]mod
 	}
 
 	// Strategy 3: Search front page stories (most popular)
-	frontPageItems, err := c.searchFrontPage(ctx, timestamp)
+	frontPageItems, err := c.searchFrontPage(ctx)
 	if err == nil {
 		// Filter front page for cloud-native relevance
 		relevantItems := c.filterRelevant(frontPageItems, keywords)
@@ -80,15 +92,15 @@ func (c *HackerNewsClient) Search(ctx context.Context, keywords []string) ([]mod
 		allItems = append(allItems, relevantItems...)
 	}
 
-	deduplicated := deduplicate(allItems)
+	deduplicated := NewDeduplicator().Deduplicate(ctx, allItems)
 	log.Printf("HN: Total unique items after deduplication: %d", len(deduplicated))
 	return deduplicated, nil
 }
 
-func (c *HackerNewsClient) searchKeyword(ctx context.Context, keyword string, timestamp int64) ([]models.NewsItem, error) {
+func (c *HackerNewsClient) searchKeyword(ctx context.Context, keyword string) ([]models.NewsItem, error) {
 	query := url.QueryEscape(keyword)
-	// Use search for recent items - numericFilters needs proper URL encoding
-	numericFilter := url.QueryEscape(fmt.Sprintf("created_at_i>%d", timestamp))
+	// Use search for items in the window - numericFilters needs proper URL encoding
+	numericFilter := c.numericFilter()
 	apiURL := fmt.Sprintf(
 		"https://hn.algolia.com/api/v1/search?query=%s&tags=story&numericFilters=%s&hitsPerPage=50",
 		query, numericFilter,
@@ -137,15 +149,17 @@ func (c *HackerNewsClient) searchKeyword(ctx context.Context, keyword string, ti
 			Description: truncate(hit.StoryText, 200),
 			PublishedAt: pubTime,
 			Category:    "community",
+			Points:      hit.Points,
+			Comments:    hit.NumComments,
 		})
 	}
 
 	return items, nil
 }
 
-func (c *HackerNewsClient) searchFrontPage(ctx context.Context, timestamp int64) ([]models.NewsItem, error) {
-	// Get recent popular stories - numericFilters needs URL encoding
-	numericFilter := url.QueryEscape(fmt.Sprintf("created_at_i>%d", timestamp))
+func (c *HackerNewsClient) searchFrontPage(ctx context.Context) ([]models.NewsItem, error) {
+	// Get popular stories in the window - numericFilters needs URL encoding
+	numericFilter := c.numericFilter()
 	apiURL := fmt.Sprintf(
 		"https://hn.algolia.com/api/v1/search?tags=front_page&numericFilters=%s&hitsPerPage=100",
 		numericFilter,
@@ -189,6 +203,8 @@ func (c *HackerNewsClient) searchFrontPage(ctx context.Context, timestamp int64)
 			Description: truncate(hit.StoryText, 200),
 			PublishedAt: pubTime,
 			Category:    "community",
+			Points:      hit.Points,
+			Comments:    hit.NumComments,
 		})
 	}
 
@@ -218,17 +234,3 @@ func truncate(s string, max int) string {
 	}
 	return s[:max] + "..."
 }
-
-func deduplicate(items []models.NewsItem) []models.NewsItem {
-	seen := make(map[string]bool)
-	var result []models.NewsItem
-
-	for _, item := range items {
-		key := strings.ToLower(item.Title)
-		if !seen[key] {
-			seen[key] = true
-			result = append(result, item)
-		}
-	}
-	return result
-}
@@ -0,0 +1,113 @@
+package news
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// Source kinds in the health report.
+const (
+	KindRSS    = "rss"
+	KindScrape = "scrape"
+)
+
+// HealthTracker collects the per-source outcome of a crawl. Failures and the
+// last successful fetch are counted on top of the previous report, so a
+// source that has been dead for weeks stands out. It is safe for
+// concurrent use.
+type HealthTracker struct {
+	mu       sync.Mutex
+	previous map[string]models.FeedHealth
+	report   []models.FeedHealth
+}
+
+// NewHealthTracker starts a report on top of the previous one (may be nil).
+func NewHealthTracker(previous []models.FeedHealth) *HealthTracker {
+	t := &HealthTracker{previous: make(map[string]models.FeedHealth)}
+	for _, h := range previous {
+		t.previous[h.URL] = h
+	}
+	return t
+}
+
+// Record adds the outcome of fetching one source. A nil err with
+// notModified set means the source answered 304 Not Modified.
+func (t *HealthTracker) Record(kind, name, url string, items int, notModified bool, err error) {
+	now := time.Now().UTC()
+	h := models.FeedHealth{
+		Name:      name,
+		URL:       url,
+		Kind:      kind,
+		ItemCount: items,
+		CheckedAt: now,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev := t.previous[url]
+	switch {
+	case err != nil:
+		h.Status = models.FeedFailed
+		h.Error = err.Error()
+		h.LastSuccessAt = prev.LastSuccessAt
+		h.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+	case notModified:
+		h.Status = models.FeedNotModified
+		h.LastSuccessAt = now
+	default:
+		h.Status = models.FeedOK
+		h.LastSuccessAt = now
+	}
+	t.report = append(t.report, h)
+}
+
+// Report returns the recorded sources ordered by kind and name.
+func (t *HealthTracker) Report() []models.FeedHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := make([]models.FeedHealth, len(t.report))
+	copy(report, t.report)
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Kind != report[j].Kind {
+			return report[i].Kind < report[j].Kind
+		}
+		return strings.ToLower(report[i].Name) < strings.ToLower(report[j].Name)
+	})
+	return report
+}
+
+// Failed returns how many of the recorded sources failed.
+func (t *HealthTracker) Failed() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := 0
+	for _, h := range t.report {
+		if h.Status == models.FeedFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// LoadFeedHealth reads a feed health report written by the crawler.
+func LoadFeedHealth(path string) ([]models.FeedHealth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report []models.FeedHealth
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse feed health %s: %w", path, err)
+	}
+	return report, nil
+}
@@ -2,64 +2,283 @@ package news
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/state"
+	"github.com/mfahlandt/lwcn/internal/window"
 	"github.com/mmcdole/gofeed"
 )
 
+const (
+	// DefaultFeedWorkers is the number of feeds fetched concurrently.
+	DefaultFeedWorkers = 6
+	// DefaultFeedTimeout bounds fetching and parsing a single feed.
+	DefaultFeedTimeout = 30 * time.Second
+)
+
 type RSSClient struct {
-	parser *gofeed.Parser
+	parser  *gofeed.Parser
+	client  *http.Client
+	state   *state.Store
+	health  *HealthTracker
+	window  window.Window
+	workers int
+	timeout time.Duration
 }
 
 func NewRSSClient() *RSSClient {
+	now := time.Now()
 	return &RSSClient{
-		parser: gofeed.NewParser(),
+		parser:  gofeed.NewParser(),
+		client:  &http.Client{},
+		window:  window.Window{Start: now.AddDate(0, 0, -7), End: now},
+		workers: DefaultFeedWorkers,
+		timeout: DefaultFeedTimeout,
+	}
+}
+
+// SetWorkers sets how many feeds are fetched concurrently.
+func (c *RSSClient) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
 	}
+	c.workers = n
+}
+
+// SetFeedTimeout sets how long a single feed may take.
+func (c *RSSClient) SetFeedTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+// UseHealth records the outcome of every feed fetched by FetchAllFeeds.
+func (c *RSSClient) UseHealth(t *HealthTracker) {
+	c.health = t
+}
+
+// UseWindow restricts results to items published within w.
+func (c *RSSClient) UseWindow(w window.Window) {
+	c.window = w
+}
+
+// UseState makes the client crawl incrementally: only items newer than the
+// feed's cursor are returned, and ETag/Last-Modified validators from the
+// previous edition are sent so unchanged feeds are not downloaded again.
+func (c *RSSClient) UseState(st *state.Store) {
+	c.state = st
 }
 
 func (c *RSSClient) FetchFeed(ctx context.Context, source models.RSSSource) ([]models.NewsItem, error) {
-	feed, err := c.parser.ParseURLWithContext(source.URL, ctx)
+	items, _, err := c.fetchFeed(ctx, source)
+	return items, err
+}
+
+// fetchFeed fetches one feed and also reports whether it was unchanged
+// since the previous edition (304 Not Modified).
+func (c *RSSClient) fetchFeed(ctx context.Context, source models.RSSSource) ([]models.NewsItem, bool, error) {
+	boilerplate, err := compileBoilerplate(source.Boilerplate)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", source.Name, err)
+	}
+
+	var cursor state.FeedCursor
+	var hasCursor bool
+	if c.state != nil {
+		cursor, hasCursor = c.state.FeedCursor(source.URL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", c.parser.UserAgent)
+	if hasCursor {
+		if cursor.ETag != "" {
+			req.Header.Set("If-None-Match", cursor.ETag)
+		}
+		if cursor.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cursor.LastModified)
+		}
+	}
+
+	checkedAt := time.Now()
+	if c.window.End.Before(checkedAt) {
+		checkedAt = c.window.End
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		// Nothing new since the previous edition; carry the cursor forward
+		if c.state != nil {
+			cursor.CheckedAt = checkedAt
+			c.state.SetFeedCursor(source.URL, cursor)
+		}
+		return nil, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%s returned status %d", source.URL, resp.StatusCode)
+	}
+
+	// gofeed parsers keep per-document state, so concurrent fetches each
+	// need their own
+	feed, err := gofeed.NewParser().Parse(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, false, fmt.Errorf("failed to parse %s: %w", source.URL, err)
+	}
+
+	since := c.window.Start
+	if hasCursor {
+		since = cursor.Since()
+	}
+
+	next := state.FeedCursor{
+		LastGUID:        cursor.LastGUID,
+		LastPublishedAt: cursor.LastPublishedAt,
+		ETag:            resp.Header.Get("ETag"),
+		LastModified:    resp.Header.Get("Last-Modified"),
+		CheckedAt:       checkedAt,
 	}
 
-	oneWeekAgo := time.Now().AddDate(0, 0, -7)
 	var items []models.NewsItem
 
 	for _, item := range feed.Items {
+		guid := item.GUID
+		if guid == "" {
+			guid = item.Link
+		}
+
 		pubDate := time.Now()
-		if item.PublishedParsed != nil {
+		dated := item.PublishedParsed != nil
+		if dated {
 			pubDate = *item.PublishedParsed
 		}
 
-		if pubDate.Before(oneWeekAgo) {
+		// Items after the window belong to the next edition. Drop the HTTP
+		// validators so the next crawl downloads the feed again and sees them.
+		if dated && !pubDate.Before(c.window.End) {
+			next.ETag = ""
+			next.LastModified = ""
+			continue
+		}
+		next.GUIDs = append(next.GUIDs, guid)
+
+		if hasCursor {
+			if guid == cursor.LastGUID || cursor.HasGUID(guid) {
+				continue
+			}
+			if dated && !pubDate.After(since) {
+				continue
+			}
+		} else if pubDate.Before(since) {
 			continue
 		}
 
-		items = append(items, models.NewsItem{
-			Title:       item.Title,
-			URL:         item.Link,
-			Source:      source.Name,
-			Description: item.Description,
-			PublishedAt: pubDate,
-			Category:    "news",
-		})
+		if dated && pubDate.After(next.LastPublishedAt) {
+			next.LastGUID = guid
+			next.LastPublishedAt = pubDate
+		}
+
+		items = append(items, feedItem(item, source, pubDate, boilerplate))
+	}
+
+	if c.state != nil {
+		c.state.SetFeedCursor(source.URL, next)
 	}
 
-	return items, nil
+	return items, false, nil
 }
 
+// FetchAllFeeds fetches the feeds concurrently, each bounded by the feed
+// timeout. A failing feed is logged and recorded in the health tracker
+// instead of aborting the crawl; items are returned in source order.
 func (c *RSSClient) FetchAllFeeds(ctx context.Context, sources []models.RSSSource) ([]models.NewsItem, error) {
+	results := make([][]models.NewsItem, len(sources))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.fetchWithTimeout(ctx, sources[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range sources {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
 	var allItems []models.NewsItem
+	for _, items := range results {
+		allItems = append(allItems, items...)
+	}
+	return allItems, ctx.Err()
+}
+
+func (c *RSSClient) fetchWithTimeout(ctx context.Context, source models.RSSSource) []models.NewsItem {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	items, notModified, err := c.fetchFeed(ctx, source)
+	if err != nil {
+		log.Printf("Feed %s failed after %s: %v", source.Name, time.Since(start).Round(time.Millisecond), err)
+	}
+	if c.health != nil {
+		c.health.Record(KindRSS, source.Name, source.URL, len(items), notModified, err)
+	}
+	return items
+}
+
+// feedItem converts a feed entry to a news item with plain-text title,
+// description and content. Feeds without a description get the start of
+// the content instead.
+func feedItem(item *gofeed.Item, source models.RSSSource, pubDate time.Time, boilerplate []*regexp.Regexp) models.NewsItem {
+	content := sanitizeFeedText(item.Content, boilerplate)
+	description := sanitizeFeedText(item.Description, boilerplate)
+	if description == "" {
+		description = excerpt(content, maxDerivedDescription)
+	}
 
-	for _, source := range sources {
-		items, err := c.FetchFeed(ctx, source)
-		if err != nil {
+	var authors []string
+	for _, a := range item.Authors {
+		if a == nil {
 			continue
 		}
-		allItems = append(allItems, items...)
+		if a.Name != "" {
+			authors = append(authors, a.Name)
+		} else {
+			authors = append(authors, a.Email)
+		}
 	}
 
-	return allItems, nil
+	return models.NewsItem{
+		Title:       HTMLToText(item.Title),
+		URL:         item.Link,
+		Source:      source.Name,
+		Description: description,
+		Content:     excerpt(content, maxContentLength),
+		Authors:     uniqueStrings(authors),
+		Categories:  uniqueStrings(item.Categories),
+		PublishedAt: pubDate,
+		Category:    "news",
+	}
 }
@@ -0,0 +1,102 @@
+package news
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxContentLength bounds the article content kept from a feed item.
+const maxContentLength = 5000
+
+// maxDerivedDescription bounds a description taken from the content.
+const maxDerivedDescription = 300
+
+// blockSelector lists elements that end a line in plain text.
+const blockSelector = "p, div, br, li, h1, h2, h3, h4, h5, h6, tr, blockquote, pre, section, article, figcaption, table, ul, ol"
+
+// defaultFeedBoilerplate removes footers that feed generators append to
+// every item, whatever the source.
+var defaultFeedBoilerplate = []*regexp.Regexp{
+	// WordPress: "The post X appeared first on Y."
+	regexp.MustCompile(`(?im)^\s*The post .+ (?:appeared first|first appeared) on .+$`),
+	// "Continue reading" and "Read more" links on a line of their own
+	regexp.MustCompile(`(?im)^\s*Continue reading\b.*$`),
+	regexp.MustCompile(`(?im)^\s*\[?Read more\]?\s*(?:→|»|…|\.\.\.)?\s*$`),
+	// Truncated excerpts end in "[…]"
+	regexp.MustCompile(`\s*\[(?:…|\.\.\.)\]\s*$`),
+}
+
+// HTMLToText converts an HTML fragment from a feed to plain text: scripts,
+// styles and images (tracking pixels) are dropped, block elements end a
+// line and entities are decoded.
+func HTMLToText(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return cleanLines(s)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return cleanLines(html.UnescapeString(s))
+	}
+	doc.Find("script, style, noscript, iframe, img, picture, svg, object, embed").Remove()
+	doc.Find(blockSelector).Each(func(_ int, sel *goquery.Selection) {
+		sel.AfterHtml("\n")
+	})
+	return cleanLines(doc.Text())
+}
+
+// cleanLines collapses whitespace within lines and drops empty lines.
+func cleanLines(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = cleanText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// compileBoilerplate compiles a source's boilerplate patterns; each is
+// matched line by line.
+func compileBoilerplate(patterns []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?m)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boilerplate pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// sanitizeFeedText converts feed HTML to plain text without the default
+// and the source's own boilerplate.
+func sanitizeFeedText(s string, boilerplate []*regexp.Regexp) string {
+	text := HTMLToText(s)
+	for _, re := range defaultFeedBoilerplate {
+		text = re.ReplaceAllString(text, "")
+	}
+	for _, re := range boilerplate {
+		text = re.ReplaceAllString(text, "")
+	}
+	return cleanLines(text)
+}
+
+// uniqueStrings trims values and drops empty and repeated ones.
+func uniqueStrings(values []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		v = cleanText(html.UnescapeString(v))
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		out = append(out, v)
+	}
+	return out
+}
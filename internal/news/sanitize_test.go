@@ -0,0 +1,88 @@
+package news
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/config"
+)
+
+func TestFeedBoilerplateFromConfig(t *testing.T) {
+	cfg, err := config.LoadNewsSources("../../config/news-sources.yaml")
+	if err != nil {
+		t.Fatalf("failed to load news sources: %v", err)
+	}
+	boilerplate := make(map[string][]string)
+	for _, src := range cfg.RSSFeeds {
+		boilerplate[src.Name] = src.Boilerplate
+	}
+
+	tests := []struct {
+		source  string
+		html    string
+		want    string
+		removed string
+	}{
+		{
+			source:  "AWS Containers",
+			html:    "<p>Amazon EKS now supports Kubernetes 1.34.</p><p>Upgrade with eksctl.</p><h2>About the authors</h2><p>Jane Doe is a Solutions Architect.</p>",
+			want:    "Amazon EKS now supports Kubernetes 1.34.\nUpgrade with eksctl.",
+			removed: "Solutions Architect",
+		},
+		{
+			source:  "InfoQ Cloud Native",
+			html:    `<img src="https://res.infoq.com/news/x.jpg"/><p>Istio 1.28 graduates ambient mode.</p> <i>By Jane Doe</i>`,
+			want:    "Istio 1.28 graduates ambient mode.",
+			removed: "Jane Doe",
+		},
+		{
+			source:  "InfoQ Cloud Native",
+			html:    "<p>By default, Istio enables mTLS.</p><p>By Steef-Jan Wiggers, Jane O'Neil and Ana López</p>",
+			want:    "By default, Istio enables mTLS.",
+			removed: "Wiggers",
+		},
+		{
+			source:  "The New Stack",
+			html:    "<p>Platform teams adopt Backstage.</p><p>YOUTUBE.COM/THENEWSTACK</p><p>Tech moves fast, don't miss an episode. Subscribe to our YouTube channel to stream all our podcasts, interviews, demos, and more.</p><p>SUBSCRIBE</p><p>The post Platform teams adopt Backstage appeared first on The New Stack.</p>",
+			want:    "Platform teams adopt Backstage.",
+			removed: "YouTube",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			patterns, ok := boilerplate[tt.source]
+			if !ok {
+				t.Fatalf("no feed %q in the config", tt.source)
+			}
+			res, err := compileBoilerplate(patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := sanitizeFeedText(tt.html, res)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if strings.Contains(got, tt.removed) {
+				t.Errorf("%q not removed", tt.removed)
+			}
+		})
+	}
+}
+
+func TestSanitizeFeedTextDefaultBoilerplate(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{"<p>Flux 2.7 is out.</p><p><a href=\"/flux\">Continue reading →</a></p>", "Flux 2.7 is out."},
+		{"<p>Flux 2.7 is out.</p><p><a href=\"/flux\">Read more…</a></p>", "Flux 2.7 is out."},
+		{"<p>Flux 2.7 is out.</p><p>[Read more]</p>", "Flux 2.7 is out."},
+		{"<p>On the project blog you can read more</p>", "On the project blog you can read more"},
+		{"<p>Teams that continue reading metrics from the old endpoint must migrate.</p>", "Teams that continue reading metrics from the old endpoint must migrate."},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFeedText(tt.html, nil); got != tt.want {
+			t.Errorf("sanitizeFeedText(%q) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+}
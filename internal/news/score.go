@@ -0,0 +1,171 @@
+package news
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// minProjectNameLength skips project names too short to match reliably.
+const minProjectNameLength = 3
+
+// maxProjectMatches caps how many mentioned projects add to a score.
+const maxProjectMatches = 2
+
+type weightedPattern struct {
+	re     *regexp.Regexp
+	weight float64
+}
+
+// Scorer rates how relevant news items are for a cloud native newsletter,
+// from keyword weights, mentions of tracked projects, the source and the
+// community engagement.
+type Scorer struct {
+	cfg      models.ScoringConfig
+	keywords []weightedPattern
+	projects []*regexp.Regexp
+}
+
+func NewScorer(cfg models.ScoringConfig) *Scorer {
+	s := &Scorer{cfg: cfg}
+	// Sorted so scores do not depend on map order
+	keywords := make([]string, 0, len(cfg.Keywords))
+	for kw := range cfg.Keywords {
+		keywords = append(keywords, kw)
+	}
+	sort.Strings(keywords)
+	for _, kw := range keywords {
+		if re := wordPattern(kw); re != nil {
+			s.keywords = append(s.keywords, weightedPattern{re: re, weight: cfg.Keywords[kw]})
+		}
+	}
+	return s
+}
+
+// UseProjects adds the names of the tracked repositories as relevance
+// signals. Extra repositories of a project ("Argo (argo-workflows)") count
+// as their project. Names are matched as written, so "Flux" is a mention
+// and "flux" is not; names in the config's ignore_projects never count.
+func (s *Scorer) UseProjects(repos []models.Repository) {
+	seen := make(map[string]bool)
+	for _, name := range s.cfg.IgnoreProjects {
+		seen[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	for _, r := range repos {
+		name := r.Name
+		if i := strings.Index(name, " ("); i > 0 {
+			name = name[:i]
+		}
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if len(key) < minProjectNameLength || seen[key] {
+			continue
+		}
+		seen[key] = true
+		s.projects = append(s.projects, boundedPattern(name))
+	}
+}
+
+// Enabled reports whether there is anything to score with.
+func (s *Scorer) Enabled() bool {
+	return len(s.keywords) > 0 || len(s.projects) > 0
+}
+
+// Score sets Score on every item and marks it as Scored.
+func (s *Scorer) Score(items []models.NewsItem) {
+	for i := range items {
+		items[i].Score = s.score(items[i])
+		items[i].Scored = true
+	}
+}
+
+func (s *Scorer) score(item models.NewsItem) float64 {
+	title := strings.ToLower(item.Title)
+	desc := strings.ToLower(item.Description)
+
+	var score float64
+	for _, kw := range s.keywords {
+		switch {
+		case kw.re.MatchString(title):
+			score += kw.weight
+		case kw.re.MatchString(desc):
+			score += kw.weight / 2
+		}
+	}
+
+	matches := 0
+	for _, re := range s.projects {
+		if matches == maxProjectMatches {
+			break
+		}
+		if re.MatchString(item.Title) || re.MatchString(item.Description) {
+			score += s.cfg.ProjectWeight
+			matches++
+		}
+	}
+
+	score += s.cfg.PointsWeight * math.Log10(1+float64(item.Points))
+	score += s.cfg.CommentsWeight * math.Log10(1+float64(item.Comments))
+
+	sources := make(map[string]bool)
+	for _, a := range item.Attributions {
+		if a.Source != item.Source {
+			sources[a.Source] = true
+		}
+	}
+	score += s.cfg.CoverageWeight * float64(len(sources))
+
+	if weight, ok := s.cfg.SourceWeights[item.Source]; ok {
+		score *= weight
+	}
+	return math.Round(score*100) / 100
+}
+
+// wordPattern matches text as a whole word or phrase, case-insensitively.
+// It is matched against lower-cased text.
+func wordPattern(text string) *regexp.Regexp {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil
+	}
+	return boundedPattern(text)
+}
+
+// boundedPattern matches text exactly, as a whole word or phrase.
+func boundedPattern(text string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|[^\pL\pN])` + regexp.QuoteMeta(text) + `(?:$|[^\pL\pN])`)
+}
+
+// SelectRelevant returns the items scoring at least minScore, highest score
+// first, capped at maxItems (0: no cap). Items of equal score keep their
+// order.
+func SelectRelevant(items []models.NewsItem, minScore float64, maxItems int) []models.NewsItem {
+	var selected []models.NewsItem
+	for _, item := range items {
+		if item.Score >= minScore {
+			selected = append(selected, item)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Score > selected[j].Score
+	})
+	if maxItems > 0 && len(selected) > maxItems {
+		selected = selected[:maxItems]
+	}
+	return selected
+}
+
+// Scored reports whether the items were scored, i.e. the news file was
+// written by a crawler with scoring enabled. Items scoring 0 count as
+// scored.
+func Scored(items []models.NewsItem) bool {
+	for _, item := range items {
+		if item.Scored {
+			return true
+		}
+	}
+	return false
+}
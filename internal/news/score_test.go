@@ -0,0 +1,57 @@
+package news
+
+import (
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+func TestScoreProjectMentions(t *testing.T) {
+	s := NewScorer(models.ScoringConfig{
+		ProjectWeight:  1.5,
+		IgnoreProjects: []string{"kind", "Harbor"},
+	})
+	s.UseProjects([]models.Repository{
+		{Name: "kind"},
+		{Name: "Harbor"},
+		{Name: "Cilium"},
+		{Name: "Flux"},
+		{Name: "Argo (argo-workflows)"},
+	})
+
+	tests := []struct {
+		name string
+		item models.NewsItem
+		want float64
+	}{
+		{"ignored word", models.NewsItem{Title: "New EC2 instances", Description: "This kind of workload runs faster."}, 0},
+		{"ignored name as written", models.NewsItem{Title: "Harbor 2.14 released"}, 0},
+		{"project name", models.NewsItem{Title: "Cilium 1.18 adds multi-pool IPAM"}, 1.5},
+		{"lower-case word", models.NewsItem{Description: "Prices are in flux this quarter."}, 0},
+		{"project name as written", models.NewsItem{Description: "Flux now supports OCI sources."}, 1.5},
+		{"extra repository counts as project", models.NewsItem{Title: "Argo Workflows 3.6"}, 1.5},
+		{"at most two projects", models.NewsItem{Title: "Cilium, Flux and Argo together"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.score(tt.item); got != tt.want {
+				t.Errorf("score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoredWithZeroScores(t *testing.T) {
+	items := []models.NewsItem{{Title: "Quarterly earnings"}, {Title: "New phone colors"}}
+	if Scored(items) {
+		t.Fatal("unscored items reported as scored")
+	}
+
+	NewScorer(models.ScoringConfig{Keywords: map[string]float64{"kubernetes": 3}}).Score(items)
+	if !Scored(items) {
+		t.Fatal("items scored 0 reported as unscored")
+	}
+	if got := SelectRelevant(items, 1.5, 0); len(got) != 0 {
+		t.Errorf("SelectRelevant kept %d irrelevant items", len(got))
+	}
+}
@@ -2,35 +2,76 @@ package news
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/mfahlandt/lwcn/internal/models"
+	"github.com/mfahlandt/lwcn/internal/window"
 )
 
+const (
+	defaultDateSelector   = "time[datetime], [datetime]"
+	defaultLinkSelector   = "a"
+	defaultMinTitleLength = 10
+)
+
+// defaultScrapeHeaders are sent with every scrape request unless a source
+// overrides them. Many news sites block simple bot User-Agents.
+var defaultScrapeHeaders = map[string]string{
+	"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+	"Accept-Language": "en-US,en;q=0.9",
+}
+
+// defaultDateLayouts are tried in order when a source declares none.
+var defaultDateLayouts = []string{
+	"2006-01-02T15:04:05.000Z", // ISO 8601 with milliseconds (e.g. "2026-01-19T18:02:06.276Z")
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02",
+}
+
 type Scraper struct {
 	client *http.Client
+	window window.Window
 }
 
 func NewScraper() *Scraper {
+	now := time.Now()
 	return &Scraper{
 		client: &http.Client{Timeout: 30 * time.Second},
+		window: window.Window{Start: now.AddDate(0, 0, -7), End: now},
 	}
 }
 
-func (s *Scraper) ScrapeHeise(ctx context.Context, source models.ScrapeSource) ([]models.NewsItem, error) {
+// UseWindow restricts results to items published within w.
+func (s *Scraper) UseWindow(w window.Window) {
+	s.window = w
+}
+
+// Scrape fetches source.URL and extracts news items using the selectors
+// declared for the source in news-sources.yaml. Items without a title, link
+// or parseable date, or outside the crawl window, are skipped.
+func (s *Scraper) Scrape(ctx context.Context, source models.ScrapeSource) ([]models.NewsItem, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use a proper browser User-Agent - Heise blocks simple bots
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	for k, v := range defaultScrapeHeaders {
+		req.Header.Set(k, v)
+	}
+	if source.Language != "" {
+		req.Header.Set("Accept-Language", source.Language+";q=0.9,en;q=0.8")
+	}
+	for k, v := range source.Headers {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -39,8 +80,7 @@ func (s *Scraper) ScrapeHeise(ctx context.Context, source models.ScrapeSource) (
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Heise returned status %d", resp.StatusCode)
-		return nil, nil
+		return nil, fmt.Errorf("%s returned status %d", source.URL, resp.StatusCode)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
@@ -48,113 +88,165 @@ func (s *Scraper) ScrapeHeise(ctx context.Context, source models.ScrapeSource) (
 		return nil, err
 	}
 
-	var items []models.NewsItem
-	oneWeekAgo := time.Now().AddDate(0, 0, -7)
-
-	// Heise uses data-component="TeaserContainer" for article teasers
-	// Try multiple selectors for different page layouts
-	selectors := []string{
-		"article[data-component='TeaserContainer']",
-		"[data-component='TeaserContainer']",
-		"article[data-teaser-name]",
-		"article",
+	baseURL, err := resolveBaseURL(source)
+	if err != nil {
+		return nil, err
 	}
 
+	// Try the primary selector first, then fallbacks for alternate page layouts
+	selectors := append([]string{source.Selector}, source.FallbackSelectors...)
 	var foundSelector string
 	for _, selector := range selectors {
+		if selector == "" {
+			continue
+		}
 		count := doc.Find(selector).Length()
 		if count > 0 {
 			foundSelector = selector
-			log.Printf("Heise: Using selector '%s' - found %d elements", selector, count)
+			log.Printf("%s: Using selector '%s' - found %d elements", source.Name, selector, count)
 			break
 		}
 	}
 
+	var items []models.NewsItem
 	if foundSelector == "" {
-		log.Printf("Heise: No matching selectors found")
+		log.Printf("%s: No matching selectors found", source.Name)
 		return items, nil
 	}
 
-	doc.Find(foundSelector).Each(func(i int, sel *goquery.Selection) {
-		// Try different ways to get the link and title
-		var link, title, desc string
-		var pubDate time.Time
-
-		// Method 1: Look for TeaserLinkContainer
-		linkSel := sel.Find("a[data-component='TeaserLinkContainer']").First()
-		if linkSel.Length() == 0 {
-			// Method 2: First link in the article
-			linkSel = sel.Find("a").First()
-		}
+	minTitleLength := source.MinTitleLength
+	if minTitleLength == 0 {
+		minTitleLength = defaultMinTitleLength
+	}
 
-		if linkSel.Length() > 0 {
-			link, _ = linkSel.Attr("href")
-			// Title might be in the link or in a heading inside
-			title = strings.TrimSpace(linkSel.Find("h2, h3, span").First().Text())
-			if title == "" {
-				title = strings.TrimSpace(linkSel.Text())
-			}
-		}
+	doc.Find(foundSelector).Each(func(i int, sel *goquery.Selection) {
+		link := extractLink(sel, source.LinkSelector)
+		title := extractTitle(sel, source.TitleSelector, source.LinkSelector)
 
-		// Look for description/synopsis
-		descSel := sel.Find("p, [class*='synopsis'], [class*='description']").First()
-		if descSel.Length() > 0 {
-			desc = strings.TrimSpace(descSel.Text())
+		var desc string
+		if source.DescriptionSelector != "" {
+			desc = cleanText(sel.Find(source.DescriptionSelector).First().Text())
 		}
 
-		// Extract publication date from time element with datetime attribute
-		timeSel := sel.Find("time[datetime], [datetime]").First()
-		if timeSel.Length() > 0 {
-			if datetime, exists := timeSel.Attr("datetime"); exists {
-				// Parse ISO 8601 datetime with milliseconds (e.g., "2026-01-19T18:02:06.276Z")
-				parsedTime, err := time.Parse("2006-01-02T15:04:05.000Z", datetime)
-				if err != nil {
-					// Try RFC3339 format
-					parsedTime, err = time.Parse(time.RFC3339, datetime)
-				}
-				if err != nil {
-					// Try alternative format without milliseconds
-					parsedTime, err = time.Parse("2006-01-02T15:04:05Z", datetime)
-				}
-				if err == nil {
-					pubDate = parsedTime
-				}
-			}
-		}
+		pubDate := extractDate(sel, source)
 
-		// Skip if no date found or older than 7 days
-		if pubDate.IsZero() || pubDate.Before(oneWeekAgo) {
+		// Skip if no date found or outside the crawl window
+		if pubDate.IsZero() || !s.window.Contains(pubDate) {
 			return
 		}
 
-		// Skip if no title or link
-		if title == "" || link == "" {
+		// Skip if no title or link, or very short titles (likely navigation elements)
+		if title == "" || link == "" || len(title) < minTitleLength {
 			return
 		}
 
-		// Clean up title (remove excessive whitespace)
-		title = strings.Join(strings.Fields(title), " ")
-
-		// Make link absolute
-		if !strings.HasPrefix(link, "http") {
-			link = "https://www.heise.de" + link
-		}
-
-		// Skip very short titles (likely navigation elements)
-		if len(title) < 10 {
+		ref, err := url.Parse(link)
+		if err != nil {
 			return
 		}
 
 		items = append(items, models.NewsItem{
 			Title:       title,
-			URL:         link,
+			URL:         baseURL.ResolveReference(ref).String(),
 			Source:      source.Name,
 			Description: desc,
 			PublishedAt: pubDate,
 			Category:    "news",
+			Language:    source.Language,
 		})
 	})
 
-	log.Printf("Heise: Scraped %d items from %s", len(items), source.URL)
+	log.Printf("%s: Scraped %d items from %s", source.Name, len(items), source.URL)
 	return items, nil
 }
+
+// resolveBaseURL returns the URL relative links are resolved against:
+// source.BaseURL if set, otherwise the scraped page URL itself.
+func resolveBaseURL(source models.ScrapeSource) (*url.URL, error) {
+	base := source.BaseURL
+	if base == "" {
+		base = source.URL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q for %s: %w", base, source.Name, err)
+	}
+	return u, nil
+}
+
+// extractLink returns the href of the first element matching linkSelector
+// inside the item, falling back to the first anchor. If the item itself is
+// an anchor its own href is used.
+func extractLink(sel *goquery.Selection, linkSelector string) string {
+	if href, ok := sel.Attr("href"); ok && goquery.NodeName(sel) == "a" {
+		return strings.TrimSpace(href)
+	}
+
+	linkSel := sel.Find(linkSelector).First()
+	if linkSelector == "" || linkSel.Length() == 0 {
+		linkSel = sel.Find(defaultLinkSelector).First()
+	}
+	href, _ := linkSel.Attr("href")
+	return strings.TrimSpace(href)
+}
+
+// extractTitle returns the text of titleSelector inside the item, falling back
+// to the text of the link element.
+func extractTitle(sel *goquery.Selection, titleSelector, linkSelector string) string {
+	if titleSelector != "" {
+		if title := cleanText(sel.Find(titleSelector).First().Text()); title != "" {
+			return title
+		}
+	}
+
+	if goquery.NodeName(sel) == "a" {
+		return cleanText(sel.Text())
+	}
+	linkSel := sel.Find(linkSelector).First()
+	if linkSelector == "" || linkSel.Length() == 0 {
+		linkSel = sel.Find(defaultLinkSelector).First()
+	}
+	return cleanText(linkSel.Text())
+}
+
+// extractDate reads the publication date from the item using the source's
+// date selector, attribute and layouts. Returns the zero time if no date
+// could be parsed.
+func extractDate(sel *goquery.Selection, source models.ScrapeSource) time.Time {
+	dateSelector := source.DateSelector
+	if dateSelector == "" {
+		dateSelector = defaultDateSelector
+	}
+	layouts := source.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	}
+
+	dateSel := sel.Find(dateSelector).First()
+	if dateSel.Length() == 0 {
+		return time.Time{}
+	}
+
+	// Prefer the configured attribute (default: datetime), fall back to text
+	attr := source.DateAttr
+	if attr == "" {
+		attr = "datetime"
+	}
+	raw, ok := dateSel.Attr(attr)
+	if !ok || strings.TrimSpace(raw) == "" {
+		raw = dateSel.Text()
+	}
+	raw = cleanText(raw)
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cleanText collapses whitespace runs into single spaces.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
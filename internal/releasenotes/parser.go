@@ -0,0 +1,271 @@
+package releasenotes
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// Release note layouts reported in models.ReleaseNotes.Format.
+const (
+	FormatGitHub         = "github"         // GitHub auto-generated "What's Changed"
+	FormatKeepAChangelog = "keepachangelog" // ### Added / Changed / Fixed ...
+	FormatConventional   = "conventional"   // Features / Bug Fixes / BREAKING CHANGES groups
+	FormatSections       = "sections"       // other headed sections
+)
+
+// maxItems caps the entries kept per category.
+const maxItems = 10
+
+// maxItemLength caps a single entry.
+const maxItemLength = 200
+
+// boldLevel is the heading level of bold lines used as headings.
+const boldLevel = 7
+
+type category int
+
+const (
+	catNone category = iota // unrecognized section, entries classified one by one
+	catSkip                 // contributors, dependencies, checksums, ...
+	catBreaking
+	catDeprecations
+	catFeatures
+	catFixes
+	catOther
+)
+
+var (
+	conventionalRe = regexp.MustCompile(`(?i)^(feat|feature|fix|bugfix|perf|refactor|docs|chore|build|ci|test|style|revert|deps)(\(([^)]*)\))?(!)?:\s*(.+)$`)
+	byAuthorRe     = regexp.MustCompile(`\s+(?:by\s+@[\w.-]+(?:\[bot\])?\s+)?in\s+https?://\S+$`)
+	mdLinkRe       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	headingWordRe  = regexp.MustCompile(`[^a-z0-9' ]+`)
+	// versionHeadingRe matches changelog version headings: "v1.2.3",
+	// "[1.2.3] - 2024-05-01", "Release 1.2.3 (2024-05-01)", "Unreleased"
+	versionHeadingRe = regexp.MustCompile(`(?i)^(?:(?:release|version)\s+)?(?:\[?v?\d+\.\d+(?:\.\d+)?[\w.+-]*\]?|\[?unreleased\]?)(?:\s*[-–—:]?\s*\(?\d{4}-\d{2}-\d{2}\)?)?$`)
+
+	keepAChangelogHeadings = map[string]bool{
+		"added": true, "changed": true, "deprecated": true, "removed": true, "fixed": true, "security": true,
+	}
+	conventionalHeadings = map[string]bool{
+		"features": true, "bug fixes": true, "breaking changes": true, "performance improvements": true, "reverts": true,
+	}
+)
+
+// Parse recognizes common changelog layouts in body and sorts the entries
+// into breaking changes, deprecations, features, fixes and other changes.
+// It returns nil when body has no list entries.
+func Parse(body string) *models.ReleaseNotes {
+	notes := &models.ReleaseNotes{}
+	current := catNone
+	// section is the category of the heading at sectionLevel; deeper headings
+	// stay in it, like "### (No, really, ...)" below "## Urgent Upgrade Notes"
+	section, sectionLevel := catNone, 0
+	headings := 0
+	keepAChangelog, conventional := 0, 0
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			level := len(m[1])
+			if level > sectionLevel && encloses(section) {
+				current = section
+				continue
+			}
+			current = classifyHeading(m[2])
+			section, sectionLevel = current, level
+			// Version headings do not tell the layout of the notes
+			if isVersionHeading(m[2]) {
+				continue
+			}
+			h := normalizeHeading(m[2])
+			headings++
+			if keepAChangelogHeadings[h] {
+				keepAChangelog++
+			}
+			if conventionalHeadings[h] {
+				conventional++
+			}
+			if strings.Contains(h, "what's changed") || strings.Contains(h, "whats changed") {
+				notes.Format = FormatGitHub
+			}
+			continue
+		}
+		if m := boldLineRe.FindStringSubmatch(line); m != nil {
+			// Bold lines rank below all headings
+			if sectionLevel < boldLevel && encloses(section) {
+				current = section
+				continue
+			}
+			current = classifyHeading(m[2])
+			section, sectionLevel = current, boldLevel
+			continue
+		}
+
+		// Nested entries usually carry details of the entry above
+		if !bulletRe.MatchString(line) || strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if current == catSkip {
+			continue
+		}
+
+		cat, text := classifyEntry(current, bulletRe.ReplaceAllString(line, ""))
+		if text == "" || cat == catSkip {
+			continue
+		}
+		add(notes, cat, text)
+	}
+
+	if notes.Format == "" {
+		switch {
+		case keepAChangelog >= 2:
+			notes.Format = FormatKeepAChangelog
+		case conventional >= 1:
+			notes.Format = FormatConventional
+		case headings > 0:
+			notes.Format = FormatSections
+		}
+	}
+	if len(notes.Breaking)+len(notes.Deprecations)+len(notes.Features)+len(notes.Fixes)+len(notes.Other) == 0 {
+		return nil
+	}
+	return notes
+}
+
+// Annotate extracts security references and the structured notes for each
+// release from its full notes (see models.Release.NotesBody).
+func Annotate(releases []models.Release) {
+	AnnotateSecurity(releases)
+	for i := range releases {
+		releases[i].Notes = Parse(releases[i].NotesBody())
+	}
+}
+
+func normalizeHeading(h string) string {
+	h = strings.ToLower(mdLinkRe.ReplaceAllString(h, "$1"))
+	h = strings.ReplaceAll(h, "’", "'")
+	return strings.TrimSpace(headingWordRe.ReplaceAllString(h, " "))
+}
+
+// classifyHeading maps a section heading to a category. Order matters:
+// "Breaking changes" must not end up in catOther via "changes". Version
+// headings of changelogs leave their entries to be classified one by one.
+func classifyHeading(h string) category {
+	if isVersionHeading(h) {
+		return catNone
+	}
+	h = normalizeHeading(h)
+	switch {
+	case containsAny(h, "contributor", "dependenc", "checksum", "sha256", "artifact", "asset", "download", "install", "docker image", "container image", "full changelog", "security"):
+		// Security sections are extracted separately by ExtractSecurity
+		return catSkip
+	case containsAny(h, "breaking", "action required", "urgent upgrade", "incompatib", "removed", "removal"):
+		return catBreaking
+	case containsAny(h, "deprecat"):
+		return catDeprecations
+	case containsAny(h, "bug", "fix"):
+		return catFixes
+	case containsAny(h, "feature", "added", "what's new", "enhancement", "improvement", "new "), h == "new":
+		return catFeatures
+	case containsAny(h, "what's changed", "whats changed", "changelog", "changes", "commits"):
+		return catNone
+	default:
+		return catOther
+	}
+}
+
+// encloses reports whether headings below a section of category c belong
+// to that section. Unrecognized sections and version headings leave their
+// subheadings to be classified on their own.
+func encloses(c category) bool {
+	return c != catNone && c != catOther
+}
+
+// isVersionHeading reports whether h names a version of a changelog.
+func isVersionHeading(h string) bool {
+	return versionHeadingRe.MatchString(strings.TrimSpace(mdLinkRe.ReplaceAllString(h, "$1")))
+}
+
+// classifyEntry refines the section category using Conventional Commit
+// prefixes and keywords, and cleans the entry text.
+func classifyEntry(section category, text string) (category, string) {
+	text = strings.TrimSpace(byAuthorRe.ReplaceAllString(text, ""))
+	text = strings.TrimSpace(mdLinkRe.ReplaceAllString(text, "$1"))
+	lower := strings.ToLower(text)
+
+	cat := section
+	if m := conventionalRe.FindStringSubmatch(text); m != nil {
+		kind := strings.ToLower(m[1])
+		text = m[5]
+		if m[3] != "" {
+			text = m[3] + ": " + text
+		}
+		switch {
+		case m[4] != "":
+			cat = catBreaking
+		case section != catNone:
+			// Headed sections win over the prefix
+		case kind == "feat" || kind == "feature":
+			cat = catFeatures
+		case kind == "fix" || kind == "bugfix":
+			cat = catFixes
+		case kind == "deps" || (kind == "chore" && strings.Contains(m[3], "deps")):
+			cat = catSkip
+		default:
+			cat = catOther
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "breaking change"):
+		cat = catBreaking
+	case cat == catNone && strings.Contains(lower, "deprecat"):
+		cat = catDeprecations
+	case cat == catNone && (strings.HasPrefix(lower, "bump ") || strings.HasPrefix(lower, "update dependency")):
+		cat = catSkip
+	case cat == catNone:
+		cat = catOther
+	}
+
+	if len(text) > maxItemLength {
+		text = strings.TrimSpace(text[:maxItemLength]) + "..."
+	}
+	return cat, text
+}
+
+// add appends text to the list for cat, skipping duplicates and entries
+// beyond maxItems.
+func add(notes *models.ReleaseNotes, cat category, text string) {
+	var list *[]string
+	switch cat {
+	case catBreaking:
+		list = &notes.Breaking
+	case catDeprecations:
+		list = &notes.Deprecations
+	case catFeatures:
+		list = &notes.Features
+	case catFixes:
+		list = &notes.Fixes
+	default:
+		list = &notes.Other
+	}
+	if len(*list) >= maxItems {
+		return
+	}
+	for _, existing := range *list {
+		if existing == text {
+			return
+		}
+	}
+	*list = append(*list, text)
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package releasenotes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyHeadingVersions(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    category
+	}{
+		{"v1.2.3", catNone},
+		{"1.2.3", catNone},
+		{"[1.2.3] - 2024-05-01", catNone},
+		{"[v1.2.3](https://github.com/o/r/compare/v1.2.2...v1.2.3) (2024-05-01)", catNone},
+		{"Release 1.2.3", catNone},
+		{"v2.0.0-rc.1", catNone},
+		{"[Unreleased]", catNone},
+		{"Bug Fixes", catFixes},
+		{"Breaking Changes in 1.2", catBreaking},
+		{"Kubernetes 1.31 support", catOther},
+	}
+	for _, tt := range tests {
+		if got := classifyHeading(tt.heading); got != tt.want {
+			t.Errorf("classifyHeading(%q) = %v, want %v", tt.heading, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionHeadingKeepsConventionalPrefixes(t *testing.T) {
+	body := `## [1.3.0] - 2024-05-01
+
+- feat: add OCI chart support
+- fix: handle empty values files
+- refactor!: drop the v1alpha1 API
+- BREAKING CHANGE: the --legacy flag is removed
+`
+	notes := Parse(body)
+	if notes == nil {
+		t.Fatal("Parse returned nil")
+	}
+	if len(notes.Features) != 1 || notes.Features[0] != "add OCI chart support" {
+		t.Errorf("Features = %q", notes.Features)
+	}
+	if len(notes.Fixes) != 1 || notes.Fixes[0] != "handle empty values files" {
+		t.Errorf("Fixes = %q", notes.Fixes)
+	}
+	if len(notes.Breaking) != 2 {
+		t.Errorf("Breaking = %q, want 2 entries", notes.Breaking)
+	}
+	if len(notes.Other) != 0 {
+		t.Errorf("Other = %q, want none", notes.Other)
+	}
+}
+
+func TestParseKubernetesSubheadings(t *testing.T) {
+	body := `## Changelog since v1.33.0
+
+## Urgent Upgrade Notes
+
+### (No, really, you MUST read this before you upgrade)
+
+- The kubelet no longer accepts the --cloud-provider flag. ([#131000](https://github.com/kubernetes/kubernetes/pull/131000), [@someone](https://github.com/someone)) [SIG Node]
+
+## Changes by Kind
+
+### Deprecation
+
+- The v1beta1 FlowSchema API is deprecated.
+
+### API Change
+
+- Added the spec.foo field to Pods.
+
+### Feature
+
+- kubeadm supports the new config format.
+
+### Bug or Regression
+
+- Fixed a panic in the scheduler.
+
+## Dependencies
+
+### Added
+_Nothing has changed._
+
+### Removed
+- github.com/old/module: v1.0.0
+`
+	notes := Parse(body)
+	if notes == nil {
+		t.Fatal("Parse returned nil")
+	}
+	if len(notes.Breaking) != 1 || !strings.HasPrefix(notes.Breaking[0], "The kubelet no longer accepts") {
+		t.Errorf("Breaking = %q, want the urgent upgrade note", notes.Breaking)
+	}
+	if len(notes.Deprecations) != 1 {
+		t.Errorf("Deprecations = %q", notes.Deprecations)
+	}
+	if len(notes.Features) != 1 || len(notes.Fixes) != 1 {
+		t.Errorf("Features = %q, Fixes = %q", notes.Features, notes.Fixes)
+	}
+	if len(notes.Other) != 1 || notes.Other[0] != "Added the spec.foo field to Pods." {
+		t.Errorf("Other = %q, want only the API change", notes.Other)
+	}
+}
@@ -0,0 +1,128 @@
+// Package releasenotes extracts structured information from release note
+// bodies so it does not depend on the LLM reading past the first few hundred
+// bytes.
+package releasenotes
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// maxSecurityNotes caps how many lines of a security section are kept.
+const maxSecurityNotes = 5
+
+// maxNoteLength caps a single security note.
+const maxNoteLength = 240
+
+var (
+	cveRe = regexp.MustCompile(`\bCVE-\d{4}-\d{4,}\b`)
+	// GHSA IDs are three groups of four characters, usually lowercase
+	ghsaRe = regexp.MustCompile(`(?i)\bGHSA-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4}\b`)
+	// Repository advisory links are preferred over the global advisory page
+	repoAdvisoryRe = regexp.MustCompile(`(?i)https://github\.com/[\w.-]+/[\w.-]+/security/advisories/(GHSA-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4})`)
+
+	headingRe  = regexp.MustCompile(`^\s*(#{1,6})\s+(.+?)\s*#*\s*$`)
+	boldLineRe = regexp.MustCompile(`^\s*(\*\*|__)([^*_]+)(\*\*|__):?\s*$`)
+	bulletRe   = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+	securityRe = regexp.MustCompile(`(?i)\b(security|vulnerabilit(y|ies)|cves?)\b`)
+)
+
+// ExtractSecurity returns the CVE and GHSA identifiers referenced in body
+// and the lines of any "Security" section (or, without one, the lines that
+// mention an identifier).
+func ExtractSecurity(body string) ([]models.SecurityFix, []string) {
+	var fixes []models.SecurityFix
+	seen := make(map[string]bool)
+	add := func(id, url string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		fixes = append(fixes, models.SecurityFix{ID: id, URL: url})
+	}
+
+	for _, m := range repoAdvisoryRe.FindAllStringSubmatch(body, -1) {
+		add(normalizeGHSA(m[1]), m[0])
+	}
+	for _, id := range cveRe.FindAllString(body, -1) {
+		add(id, "https://www.cve.org/CVERecord?id="+id)
+	}
+	for _, id := range ghsaRe.FindAllString(body, -1) {
+		id = normalizeGHSA(id)
+		add(id, "https://github.com/advisories/"+id)
+	}
+
+	notes := securitySectionLines(body)
+	if len(notes) == 0 && len(fixes) > 0 {
+		for _, line := range strings.Split(body, "\n") {
+			if cveRe.MatchString(line) || ghsaRe.MatchString(line) {
+				notes = appendNote(notes, line)
+			}
+		}
+	}
+
+	return fixes, notes
+}
+
+// AnnotateSecurity sets SecurityFixes and SecurityNotes on each release.
+func AnnotateSecurity(releases []models.Release) {
+	for i := range releases {
+		releases[i].SecurityFixes, releases[i].SecurityNotes = ExtractSecurity(releases[i].NotesBody())
+	}
+}
+
+// securitySectionLines collects the content lines below headings (or bold
+// lines used as headings) that mention security.
+func securitySectionLines(body string) []string {
+	var notes []string
+	inSection := false
+	level := 0
+
+	for _, line := range strings.Split(body, "\n") {
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			if inSection && len(m[1]) > level {
+				// Subheadings such as "### CVE-2026-1234" stay in the section
+				notes = appendNote(notes, m[2])
+				continue
+			}
+			inSection = securityRe.MatchString(m[2])
+			level = len(m[1])
+			continue
+		}
+		if m := boldLineRe.FindStringSubmatch(line); m != nil {
+			inSection = securityRe.MatchString(m[2])
+			level = 7
+			continue
+		}
+		if inSection {
+			notes = appendNote(notes, line)
+		}
+	}
+
+	return notes
+}
+
+func appendNote(notes []string, line string) []string {
+	if len(notes) >= maxSecurityNotes {
+		return notes
+	}
+	line = strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+	if line == "" || strings.HasPrefix(line, "<!--") {
+		return notes
+	}
+	if len(line) > maxNoteLength {
+		line = strings.TrimSpace(line[:maxNoteLength]) + "..."
+	}
+	for _, n := range notes {
+		if n == line {
+			return notes
+		}
+	}
+	return append(notes, line)
+}
+
+func normalizeGHSA(id string) string {
+	return "GHSA-" + strings.ToLower(id[len("GHSA-"):])
+}
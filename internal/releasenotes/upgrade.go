@@ -0,0 +1,59 @@
+package releasenotes
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+var (
+	breakingKeywordRe    = regexp.MustCompile(`\bBREAKING\b|(?i)\b(breaking[ -]changes?|no longer (supported|available|works)|(has|have) been removed|removed support|incompatible changes?|action required)\b`)
+	deprecationKeywordRe = regexp.MustCompile(`(?i)\b(deprecat(ed|es|ion|ing|e))\b`)
+)
+
+// UpgradeNotes returns the breaking changes and deprecations of a release.
+// Structured notes are used when present; otherwise the body is scanned
+// line by line for the usual keywords, so prose-only notes still count.
+// Breaking changes are also scanned for when only deprecations were parsed,
+// since they are often mentioned in the entries of other sections.
+func UpgradeNotes(r models.Release) (breaking, deprecations []string) {
+	if r.Notes != nil {
+		breaking, deprecations = r.Notes.Breaking, r.Notes.Deprecations
+	}
+	if len(breaking) > 0 {
+		return breaking, deprecations
+	}
+
+	scanDeprecations := len(deprecations) == 0
+	listed := make(map[string]bool, len(deprecations))
+	for _, d := range deprecations {
+		listed[d] = true
+	}
+	for _, line := range strings.Split(r.NotesBody(), "\n") {
+		// Headings only announce a section whose entries were parsed above
+		if headingRe.MatchString(line) || boldLineRe.MatchString(line) {
+			continue
+		}
+		_, text := classifyEntry(catOther, bulletRe.ReplaceAllString(line, ""))
+		if text == "" || listed[text] {
+			continue
+		}
+		switch {
+		case breakingKeywordRe.MatchString(text) && len(breaking) < maxItems:
+			breaking = appendUnique(breaking, text)
+		case scanDeprecations && deprecationKeywordRe.MatchString(text) && len(deprecations) < maxItems:
+			deprecations = appendUnique(deprecations, text)
+		}
+	}
+	return breaking, deprecations
+}
+
+func appendUnique(list []string, text string) []string {
+	for _, existing := range list {
+		if existing == text {
+			return list
+		}
+	}
+	return append(list, text)
+}
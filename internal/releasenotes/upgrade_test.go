@@ -0,0 +1,42 @@
+package releasenotes
+
+import (
+	"testing"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+func TestUpgradeNotesScansForBreakingNextToDeprecations(t *testing.T) {
+	body := `## Changes by Kind
+
+### Deprecation
+
+- The v1beta1 FlowSchema API is deprecated and will be removed in v1.36.
+
+### Other (Cleanup or Flake)
+
+- Support for cgroup v1 has been removed from the kubelet.
+- Updated the base image.
+`
+	r := models.Release{Body: body}
+	r.Notes = Parse(body)
+
+	breaking, deprecations := UpgradeNotes(r)
+	if len(breaking) != 1 || breaking[0] != "Support for cgroup v1 has been removed from the kubelet." {
+		t.Errorf("breaking = %q, want the removed cgroup v1 support", breaking)
+	}
+	if len(deprecations) != 1 || deprecations[0] != "The v1beta1 FlowSchema API is deprecated and will be removed in v1.36." {
+		t.Errorf("deprecations = %q", deprecations)
+	}
+}
+
+func TestUpgradeNotesKeepsParsedBreaking(t *testing.T) {
+	r := models.Release{
+		Body:  "- Support for cgroup v1 has been removed.",
+		Notes: &models.ReleaseNotes{Breaking: []string{"Drop the v1alpha1 API"}},
+	}
+	breaking, _ := UpgradeNotes(r)
+	if len(breaking) != 1 || breaking[0] != "Drop the v1alpha1 API" {
+		t.Errorf("breaking = %q, want only the parsed entry", breaking)
+	}
+}
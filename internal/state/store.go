@@ -0,0 +1,220 @@
+// Package state persists incremental crawl cursors (last seen release per
+// repository, last seen item and HTTP validators per feed) so every edition
+// contains exactly the items published since the previous edition, no
+// matter how late the weekly cron actually runs.
+//
+// Cursors are kept per edition: "previous" holds the cursors as they were
+// when the current edition started and is what crawlers read, "current"
+// holds the cursors after the latest crawl and is what crawlers write.
+// Re-running a crawler for the same edition therefore reproduces the same
+// window instead of returning nothing.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultPath is where the crawl state lives next to the crawled data.
+const DefaultPath = "data/crawl-state.json"
+
+// maxFeedGUIDs bounds the number of remembered item GUIDs per feed.
+const maxFeedGUIDs = 200
+
+// RepoCursor records what was seen for one repository.
+type RepoCursor struct {
+	LastReleaseID   int64     `json:"last_release_id,omitempty"`
+	LastTag         string    `json:"last_tag,omitempty"`
+	LastPublishedAt time.Time `json:"last_published_at,omitempty"`
+	// CheckedAt is the exclusive upper bound of the last crawl window;
+	// releases published at or after it are new.
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Since returns the lower bound of the next crawl window.
+func (c RepoCursor) Since() time.Time {
+	if c.CheckedAt.After(c.LastPublishedAt) {
+		return c.CheckedAt
+	}
+	return c.LastPublishedAt
+}
+
+// FeedCursor records what was seen for one RSS/Atom feed.
+type FeedCursor struct {
+	LastGUID        string    `json:"last_guid,omitempty"`
+	LastPublishedAt time.Time `json:"last_published_at,omitempty"`
+	ETag            string    `json:"etag,omitempty"`
+	LastModified    string    `json:"last_modified,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+	// GUIDs lists items present in the feed at the last crawl, so undated
+	// items are not reported again.
+	GUIDs []string `json:"guids,omitempty"`
+}
+
+// Since returns the lower bound of the next crawl window.
+func (c FeedCursor) Since() time.Time {
+	if c.CheckedAt.After(c.LastPublishedAt) {
+		return c.CheckedAt
+	}
+	return c.LastPublishedAt
+}
+
+// HasGUID reports whether guid was present at the last crawl.
+func (c FeedCursor) HasGUID(guid string) bool {
+	for _, g := range c.GUIDs {
+		if g == guid {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursors is one snapshot of all repository and feed cursors.
+type Cursors struct {
+	Repos map[string]RepoCursor `json:"repos"`
+	Feeds map[string]FeedCursor `json:"feeds"`
+}
+
+func newCursors() Cursors {
+	return Cursors{
+		Repos: make(map[string]RepoCursor),
+		Feeds: make(map[string]FeedCursor),
+	}
+}
+
+func (c Cursors) clone() Cursors {
+	out := newCursors()
+	for k, v := range c.Repos {
+		out.Repos[k] = v
+	}
+	for k, v := range c.Feeds {
+		out.Feeds[k] = v
+	}
+	return out
+}
+
+type fileLayout struct {
+	Edition  string  `json:"edition"`
+	Previous Cursors `json:"previous"`
+	Current  Cursors `json:"current"`
+}
+
+// Store is a crawl state file. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data fileLayout
+}
+
+// Load reads the state file at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: fileLayout{Previous: newCursors(), Current: newCursors()},
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse crawl state %s: %w", path, err)
+	}
+	// Older or hand-edited files may lack one of the maps
+	s.data.Previous = mergeCursors(newCursors(), s.data.Previous)
+	s.data.Current = mergeCursors(newCursors(), s.data.Current)
+	return s, nil
+}
+
+func mergeCursors(dst, src Cursors) Cursors {
+	for k, v := range src.Repos {
+		dst.Repos[k] = v
+	}
+	for k, v := range src.Feeds {
+		dst.Feeds[k] = v
+	}
+	return dst
+}
+
+// BeginEdition selects the edition being crawled (e.g. "2026-week-17"). When it
+// differs from the stored edition, the current cursors become the baseline
+// for the new edition.
+func (s *Store) BeginEdition(edition string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Edition == edition {
+		return
+	}
+	s.data.Previous = s.data.Current.clone()
+	s.data.Edition = edition
+}
+
+// Edition returns the edition selected by BeginEdition.
+func (s *Store) Edition() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.Edition
+}
+
+// RepoCursor returns the baseline cursor for a repository ("owner/repo").
+func (s *Store) RepoCursor(key string) (RepoCursor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.data.Previous.Repos[key]
+	return c, ok
+}
+
+// SetRepoCursor records the cursor after crawling a repository.
+func (s *Store) SetRepoCursor(key string, c RepoCursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Current.Repos[key] = c
+}
+
+// FeedCursor returns the baseline cursor for a feed URL.
+func (s *Store) FeedCursor(key string) (FeedCursor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.data.Previous.Feeds[key]
+	return c, ok
+}
+
+// SetFeedCursor records the cursor after crawling a feed.
+func (s *Store) SetFeedCursor(key string, c FeedCursor) {
+	if len(c.GUIDs) > maxFeedGUIDs {
+		c.GUIDs = c.GUIDs[:maxFeedGUIDs]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Current.Feeds[key] = c
+}
+
+// Save writes the state back to disk atomically.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal crawl state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write crawl state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,218 @@
+// Package version parses release tags into semantic versions and classifies
+// releases as major, minor, patch or prerelease. It understands the tag
+// shapes common in the cloud native ecosystem: "v1.2.3", "1.2", component
+// prefixes ("api/v1.2.3", "helm-chart-1.0.0"), SemVer prereleases
+// ("v1.2.3-rc.1") and PEP 440 style suffixes ("1.2.3rc1", "1.2.3.dev0").
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mfahlandt/lwcn/internal/models"
+)
+
+// Release types stored in models.Release.ReleaseType and models.Release.Bump.
+const (
+	Major      = "major"
+	Minor      = "minor"
+	Patch      = "patch"
+	Prerelease = "prerelease"
+)
+
+// tagRe splits a tag into prefix, numeric core and suffix. The prefix is
+// lazy so the version is taken from the end of the tag.
+var tagRe = regexp.MustCompile(`^(.*?)v?(\d+)\.(\d+)(?:\.(\d+))?([-.+_]?[0-9A-Za-z][0-9A-Za-z.+\-_]*)?$`)
+
+// prereleaseRe matches prerelease labels such as rc1, beta.2, alpha, dev0.
+var prereleaseRe = regexp.MustCompile(`^(rc|alpha|beta|a|b|pre|preview|dev|test|next|canary|nightly|snapshot|snap|edge|ea|m|milestone)\d*$`)
+
+// prereleasePrefixes mark whole release channels as unstable (e.g. Linkerd
+// "edge-24.1.2").
+var prereleasePrefixes = []string{"edge", "nightly", "canary", "snapshot", "dev"}
+
+// Version is a parsed release tag.
+type Version struct {
+	Raw string
+	// Prefix is everything before the version, e.g. "api/", "helm-chart-".
+	Prefix string
+	Major  int
+	Minor  int
+	Patch  int
+	// Suffix is the remainder after the numeric core, without separator.
+	Suffix string
+	// Prerelease is set when the suffix or prefix marks an unstable build.
+	Prerelease bool
+}
+
+// Parse parses tag. ok is false for tags without a MAJOR.MINOR core or
+// whose prefix is glued to the number (e.g. "abc1.2").
+func Parse(tag string) (v Version, ok bool) {
+	m := tagRe.FindStringSubmatch(strings.TrimSpace(tag))
+	if m == nil {
+		return Version{}, false
+	}
+
+	prefix := m[1]
+	if prefix != "" && !strings.ContainsAny(prefix[len(prefix)-1:], "/-_@ ") {
+		return Version{}, false
+	}
+
+	v.Raw = tag
+	v.Prefix = prefix
+	v.Major, _ = strconv.Atoi(m[2])
+	v.Minor, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		v.Patch, _ = strconv.Atoi(m[4])
+	}
+	v.Suffix = strings.TrimLeft(m[5], "-._")
+	v.Prerelease = isPrereleaseSuffix(v.Suffix) || isPrereleasePrefix(prefix)
+	return v, true
+}
+
+func isPrereleaseSuffix(suffix string) bool {
+	// Build metadata never makes a version unstable
+	if i := strings.Index(suffix, "+"); i >= 0 {
+		suffix = suffix[:i]
+	}
+	if suffix == "" {
+		return false
+	}
+	// Only the first identifier counts: "rc.1" -> "rc", "beta2-amd64" -> "beta2"
+	first := strings.FieldsFunc(strings.ToLower(suffix), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	})
+	if len(first) == 0 {
+		return false
+	}
+	// PEP 440 style "0rc1" after a fourth numeric component is rare; a
+	// leading digit means a fourth version component, not a prerelease
+	return prereleaseRe.MatchString(first[0])
+}
+
+func isPrereleasePrefix(prefix string) bool {
+	tokens := strings.FieldsFunc(strings.ToLower(prefix), func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '@' || r == ' '
+	})
+	for _, t := range tokens {
+		for _, p := range prereleasePrefixes {
+			if t == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compare returns -1, 0 or 1 ordering v against o by numeric core; a
+// prerelease sorts before the release with the same core.
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if v.Prerelease != o.Prerelease {
+		if v.Prerelease {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(v.Suffix, o.Suffix)
+}
+
+// Type classifies the version by its own shape: x.0.0 is major, x.y.0 is
+// minor, everything else patch. Prereleases are always Prerelease.
+func (v Version) Type() string {
+	switch {
+	case v.Prerelease:
+		return Prerelease
+	case v.Minor == 0 && v.Patch == 0:
+		return Major
+	case v.Patch == 0:
+		return Minor
+	default:
+		return Patch
+	}
+}
+
+// BumpFrom returns which component changed from prev to v.
+func (v Version) BumpFrom(prev Version) string {
+	switch {
+	case v.Major != prev.Major:
+		return Major
+	case v.Minor != prev.Minor:
+		return Minor
+	case v.Patch != prev.Patch:
+		return Patch
+	default:
+		return Prerelease
+	}
+}
+
+// IsPrerelease reports whether tag denotes an unstable release. Tags that do
+// not parse as versions are checked for prerelease labels as whole words.
+func IsPrerelease(tag string) bool {
+	if v, ok := Parse(tag); ok {
+		return v.Prerelease
+	}
+	return isPrereleasePrefix(tag) || isPrereleaseSuffix(tag)
+}
+
+// Annotate sets ReleaseType, PreviousTag and Bump on each release. history
+// holds every tag seen for the same repository (in or before the window) and
+// is used to find each release's predecessor: the highest lower version with
+// the same prefix, restricted to stable versions for stable releases.
+func Annotate(releases []models.Release, history []string) {
+	parsed := make([]Version, 0, len(history))
+	for _, tag := range history {
+		if v, ok := Parse(tag); ok {
+			parsed = append(parsed, v)
+		}
+	}
+
+	for i := range releases {
+		v, ok := Parse(releases[i].TagName)
+		if !ok {
+			continue
+		}
+		releases[i].ReleaseType = v.Type()
+
+		var prev *Version
+		for j := range parsed {
+			p := parsed[j]
+			if p.Prefix != v.Prefix || p.Compare(v) >= 0 || (!v.Prerelease && p.Prerelease) {
+				continue
+			}
+			if prev == nil || p.Compare(*prev) > 0 {
+				prev = &parsed[j]
+			}
+		}
+		if prev != nil {
+			releases[i].PreviousTag = prev.Raw
+			releases[i].Bump = v.BumpFrom(*prev)
+			if v.Prerelease {
+				releases[i].Bump = Prerelease
+			}
+		}
+	}
+}
+
+// IsNotable reports whether a release is a stable major or minor release,
+// judged by the bump from its predecessor when known, else by its shape.
+func IsNotable(r models.Release) bool {
+	kind := r.Bump
+	if kind == "" {
+		kind = r.ReleaseType
+	}
+	if kind == "" {
+		if v, ok := Parse(r.TagName); ok {
+			kind = v.Type()
+		}
+	}
+	return kind == Major || kind == Minor
+}
@@ -0,0 +1,117 @@
+package window
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	weekLabelRe  = regexp.MustCompile(`^(\d{4})-week-(\d{2})$`)
+	rangeLabelRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-to-(\d{4}-\d{2}-\d{2})$`)
+)
+
+// ParseLabel returns the window a Label names.
+func ParseLabel(label string) (Window, error) {
+	if m := weekLabelRe.FindStringSubmatch(label); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		if week < 1 || week > WeeksInYear(year) {
+			return Window{}, fmt.Errorf("invalid week in label %q", label)
+		}
+		return ForISOWeek(year, week), nil
+	}
+	if m := rangeLabelRe.FindStringSubmatch(label); m != nil {
+		start, err := time.Parse(dateLayout, m[1])
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid start date in label %q: %w", label, err)
+		}
+		end, err := time.Parse(dateLayout, m[2])
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid end date in label %q: %w", label, err)
+		}
+		end = end.AddDate(0, 0, 1)
+		if !end.After(start) {
+			return Window{}, fmt.Errorf("label %q ends before it starts", label)
+		}
+		return Window{Start: start, End: end}, nil
+	}
+	return Window{}, fmt.Errorf("not a window label: %q", label)
+}
+
+// File is a data file named after the window it covers, like
+// "news-2026-week-41.json".
+type File struct {
+	Path    string
+	Window  Window
+	ModTime time.Time
+}
+
+// Files returns the files in dir named prefix followed by a window label,
+// ordered by the end of their window. Week and custom range labels do not
+// sort by name, so the name is not used for ordering. Files whose windows
+// end at the same time are ordered by modification time.
+func Files(dir, prefix string) []File {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		label := strings.TrimSuffix(strings.TrimPrefix(name, prefix), filepath.Ext(name))
+		w, err := ParseLabel(label)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{Path: filepath.Join(dir, name), Window: w, ModTime: info.ModTime()})
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if !files[i].Window.End.Equal(files[j].Window.End) {
+			return files[i].Window.End.Before(files[j].Window.End)
+		}
+		return files[i].ModTime.Before(files[j].ModTime)
+	})
+	return files
+}
+
+// Latest returns the path of the file in dir named prefix followed by the
+// window label that ends last, or "" if there is none.
+func Latest(dir, prefix string) string {
+	files := Files(dir, prefix)
+	if len(files) == 0 {
+		return ""
+	}
+	return files[len(files)-1].Path
+}
+
+// Sibling returns the path of the file next to path that covers the same
+// window under another prefix: for "data/releases-2026-week-41.json",
+// "releases-" and "advisories-" it is "data/advisories-2026-week-41.json".
+// It returns "" if path is not named from followed by a window label.
+func Sibling(path, from, prefix string) string {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, from) {
+		return ""
+	}
+	ext := filepath.Ext(name)
+	label := strings.TrimSuffix(strings.TrimPrefix(name, from), ext)
+	if _, err := ParseLabel(label); err != nil {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), prefix+label+ext)
+}
@@ -0,0 +1,22 @@
+package window
+
+import "testing"
+
+func TestSibling(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"data/releases-2026-week-41.json", "data/advisories-2026-week-41.json"},
+		{"data/releases-2026-04-01-to-2026-04-14.json", "data/advisories-2026-04-01-to-2026-04-14.json"},
+		{"releases-2026-week-41.json", "advisories-2026-week-41.json"},
+		{"data/releases.json", ""},
+		{"data/news-2026-week-41.json", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Sibling(tt.path, "releases-", "advisories-"); got != tt.want {
+			t.Errorf("Sibling(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
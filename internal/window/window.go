@@ -0,0 +1,139 @@
+// Package window defines the crawl time window shared by all crawler
+// commands, so a regenerated edition uses exactly the same window as the
+// live run. By default the window is the last completed ISO week
+// (Monday 00:00 UTC to the following Monday 00:00 UTC).
+package window
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Window is the half-open interval [Start, End).
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t lies within the window.
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// ISOWeek reports the ISO year and week of the window start, and whether
+// the window spans exactly that ISO week.
+func (w Window) ISOWeek() (year, week int, ok bool) {
+	year, week = w.Start.ISOWeek()
+	ok = w.Start.Equal(WeekStart(year, week)) && w.End.Equal(w.Start.AddDate(0, 0, 7))
+	return year, week, ok
+}
+
+// Label names data files for this window: "2026-week-17" for ISO weeks,
+// "2026-04-01-to-2026-04-07" for custom ranges (end date inclusive).
+func (w Window) Label() string {
+	if year, week, ok := w.ISOWeek(); ok {
+		return fmt.Sprintf("%d-week-%02d", year, week)
+	}
+	return fmt.Sprintf("%s-to-%s", w.Start.Format(dateLayout), w.End.Add(-time.Nanosecond).Format(dateLayout))
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%s to %s", w.Start.Format("2006-01-02 15:04"), w.End.Format("2006-01-02 15:04"))
+}
+
+// WeekStart returns Monday 00:00 UTC of the given ISO week.
+func WeekStart(year, week int) time.Time {
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	weekday := int(jan4.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	week1Monday := jan4.AddDate(0, 0, -(weekday - 1))
+	return week1Monday.AddDate(0, 0, (week-1)*7)
+}
+
+// WeeksInYear returns the number of ISO weeks of year, 52 or 53. December
+// 28th is always in the last ISO week.
+func WeeksInYear(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// ForISOWeek returns the window covering the given ISO week.
+func ForISOWeek(year, week int) Window {
+	start := WeekStart(year, week)
+	return Window{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// LastCompletedWeek returns the ISO week before the one containing now.
+func LastCompletedWeek(now time.Time) Window {
+	year, week := now.UTC().AddDate(0, 0, -7).ISOWeek()
+	return ForISOWeek(year, week)
+}
+
+// Flags holds the shared window flags of the crawler commands.
+type Flags struct {
+	year *int
+	week *int
+	from *string
+	to   *string
+}
+
+// RegisterFlags adds -year, -week, -from and -to to the default flag set.
+// Call before flag.Parse.
+func RegisterFlags() *Flags {
+	return &Flags{
+		year: flag.Int("year", 0, "ISO year of the week to crawl (default: year of -week, or last completed week)"),
+		week: flag.Int("week", 0, "ISO week to crawl (default: last completed week)"),
+		from: flag.String("from", "", "Start date YYYY-MM-DD (inclusive, UTC); overrides -year/-week"),
+		to:   flag.String("to", "", "End date YYYY-MM-DD (inclusive, UTC); requires -from"),
+	}
+}
+
+// Explicit reports whether any window flag was set.
+func (f *Flags) Explicit() bool {
+	return *f.year != 0 || *f.week != 0 || *f.from != "" || *f.to != ""
+}
+
+// Resolve returns the window selected by the flags, defaulting to the last
+// completed ISO week relative to now.
+func (f *Flags) Resolve(now time.Time) (Window, error) {
+	if *f.from != "" || *f.to != "" {
+		if *f.from == "" || *f.to == "" {
+			return Window{}, fmt.Errorf("-from and -to must be used together")
+		}
+		start, err := time.Parse(dateLayout, *f.from)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid -from date: %w", err)
+		}
+		end, err := time.Parse(dateLayout, *f.to)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid -to date: %w", err)
+		}
+		end = end.AddDate(0, 0, 1)
+		if !end.After(start) {
+			return Window{}, fmt.Errorf("-to must not be before -from")
+		}
+		return Window{Start: start, End: end}, nil
+	}
+
+	if *f.week != 0 {
+		year := *f.year
+		if year == 0 {
+			year, _ = now.UTC().ISOWeek()
+		}
+		if weeks := WeeksInYear(year); *f.week < 1 || *f.week > weeks {
+			return Window{}, fmt.Errorf("invalid -week %d: %d has %d ISO weeks", *f.week, year, weeks)
+		}
+		return ForISOWeek(year, *f.week), nil
+	}
+	if *f.year != 0 {
+		return Window{}, fmt.Errorf("-year requires -week")
+	}
+
+	return LastCompletedWeek(now), nil
+}
@@ -0,0 +1,58 @@
+package window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeksInYear(t *testing.T) {
+	for year, want := range map[int]int{2020: 53, 2021: 52, 2025: 52, 2026: 53, 2027: 52} {
+		if got := WeeksInYear(year); got != want {
+			t.Errorf("WeeksInYear(%d) = %d, want %d", year, got, want)
+		}
+	}
+}
+
+func TestResolveWeek(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		year, week int
+		wantStart  string
+		wantErr    bool
+	}{
+		{2026, 53, "2026-12-28", false},
+		{2020, 53, "2020-12-28", false},
+		{2025, 53, "", true},
+		{2025, 52, "2025-12-22", false},
+		{0, 41, "2026-10-05", false},
+		{2026, 0, "", true},
+		{2026, 54, "", true},
+	}
+	for _, tt := range tests {
+		year, week, empty := tt.year, tt.week, ""
+		f := &Flags{year: &year, week: &week, from: &empty, to: &empty}
+		w, err := f.Resolve(now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("-year %d -week %d: got %v, want an error", tt.year, tt.week, w)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("-year %d -week %d: %v", tt.year, tt.week, err)
+			continue
+		}
+		if got := w.Start.Format(dateLayout); got != tt.wantStart {
+			t.Errorf("-year %d -week %d starts %s, want %s", tt.year, tt.week, got, tt.wantStart)
+		}
+	}
+}
+
+func TestParseLabelRejectsMissingWeek53(t *testing.T) {
+	if _, err := ParseLabel("2025-week-53"); err == nil {
+		t.Error("ParseLabel(2025-week-53) succeeded, 2025 has 52 ISO weeks")
+	}
+	if _, err := ParseLabel("2026-week-53"); err != nil {
+		t.Errorf("ParseLabel(2026-week-53): %v", err)
+	}
+}